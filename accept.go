@@ -0,0 +1,123 @@
+package srcset
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PictureSources holds the candidates of a picture element keyed by MIME
+// type, as rendered by RenderPicture.
+type PictureSources map[string]SourceSet
+
+// explicitTypes are the image types that a client must list in its Accept
+// header to be considered to support them. Browsers that do not support them
+// still send wildcards such as image/*, so a wildcard is no evidence of
+// support.
+var explicitTypes = map[string]bool{
+	"image/avif": true,
+	"image/jxl":  true,
+	"image/webp": true,
+}
+
+// FilterByAccept returns the sources whose type the client accepts according
+// to acceptHeader, the value of its Accept header. Types are matched against
+// the media ranges of the header, the most specific range deciding, and are
+// removed if they match none or only with q=0. Wildcards do not match
+// image/avif, image/jxl and image/webp, which clients announce explicitly.
+//
+// Sources without type, and the source RenderPicture would use for the
+// fallback img element, are always kept. An empty header accepts all types.
+func (p PictureSources) FilterByAccept(acceptHeader string) PictureSources {
+	filtered := make(PictureSources, len(p))
+	if len(p) == 0 {
+		return filtered
+	}
+	if strings.TrimSpace(acceptHeader) == "" {
+		for typ, set := range p {
+			filtered[typ] = set
+		}
+		return filtered
+	}
+
+	var (
+		ranges   = parseAccept(acceptHeader)
+		types    = sortedTypes(p)
+		fallback = types[len(types)-1]
+	)
+	for _, typ := range types {
+		if typ == "" || typ == fallback || acceptQuality(ranges, typ) > 0 {
+			filtered[typ] = p[typ]
+		}
+	}
+	return filtered
+}
+
+// acceptRange is a media range of an Accept header with its quality.
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept parses the media ranges of an Accept header, skipping invalid
+// ones.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		typ, subtype, ok := cutType(params[0])
+		if !ok {
+			continue
+		}
+		r := acceptRange{typ: typ, subtype: subtype, q: 1}
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(param, "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				r.q = q
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// cutType splits a MIME type into its lowercase type and subtype.
+func cutType(s string) (typ, subtype string, ok bool) {
+	typ, subtype, ok = strings.Cut(strings.ToLower(strings.TrimSpace(s)), "/")
+	return typ, subtype, ok && typ != "" && subtype != ""
+}
+
+// acceptQuality returns the quality of the most specific of ranges matching
+// typ, or 0 if none matches.
+func acceptQuality(ranges []acceptRange, typ string) float64 {
+	t, sub, ok := cutType(typ)
+	if !ok {
+		return 0
+	}
+
+	var (
+		q           float64
+		specificity = -1
+	)
+	for _, r := range ranges {
+		var s int
+		switch {
+		case r.typ == t && r.subtype == sub:
+			s = 2
+		case explicitTypes[t+"/"+sub]:
+			continue
+		case r.typ == t && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
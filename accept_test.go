@@ -0,0 +1,48 @@
+package srcset
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_FilterByAccept(t *testing.T) {
+	sources := PictureSources{
+		"image/avif": Parse("a.avif"),
+		"image/webp": Parse("a.webp"),
+		"image/png":  Parse("a.png"),
+		"image/jpeg": Parse("a.jpg"),
+		"":           Parse("a"),
+	}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   []string
+	}{
+		{name: "Empty", accept: "", want: []string{"", "image/avif", "image/jpeg", "image/png", "image/webp"}},
+		{name: "Modern browser", accept: "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8", want: []string{"", "image/avif", "image/jpeg", "image/png", "image/webp"}},
+		{name: "Old browser", accept: "image/webp,image/apng,image/*,*/*;q=0.8", want: []string{"", "image/jpeg", "image/png", "image/webp"}},
+		{name: "Wildcard only", accept: "*/*", want: []string{"", "image/jpeg", "image/png"}},
+		{name: "Rejected", accept: "image/avif;q=0, image/webp; Q=0.5, image/png;q=0", want: []string{"", "image/jpeg", "image/webp"}},
+		{name: "Specific over wildcard", accept: "image/*;q=0, IMAGE/PNG", want: []string{"", "image/jpeg", "image/png"}},
+		{name: "Invalid ranges", accept: "image, /png, webp/", want: []string{"", "image/jpeg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for typ := range sources.FilterByAccept(tt.accept) {
+				got = append(got, typ)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. FilterByAccept() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got := (PictureSources{}).FilterByAccept("image/png"); len(got) != 0 {
+		t.Errorf("FilterByAccept() = %v, want no sources", got)
+	}
+}
@@ -0,0 +1,106 @@
+package srcset
+
+// URLs returns the URLs of all candidates, in order.
+func (s SourceSet) URLs() []string {
+	urls := make([]string, 0, len(s))
+	for _, src := range s {
+		urls = append(urls, src.URL)
+	}
+	return urls
+}
+
+// Widths returns the declared widths of the candidates, in order. Candidates
+// without a width descriptor are skipped.
+func (s SourceSet) Widths() []int64 {
+	widths := []int64{}
+	for _, src := range s {
+		if src.Width != nil {
+			widths = append(widths, *src.Width)
+		}
+	}
+	return widths
+}
+
+// Heights returns the declared heights of the candidates, in order.
+// Candidates without a height descriptor are skipped.
+func (s SourceSet) Heights() []int64 {
+	heights := []int64{}
+	for _, src := range s {
+		if src.Height != nil {
+			heights = append(heights, *src.Height)
+		}
+	}
+	return heights
+}
+
+// Densities returns the declared densities of the candidates, in order.
+// Candidates without a density descriptor are skipped.
+func (s SourceSet) Densities() []float64 {
+	densities := []float64{}
+	for _, src := range s {
+		if src.Density != nil {
+			densities = append(densities, *src.Density)
+		}
+	}
+	return densities
+}
+
+// ByWidth returns the first candidate with width descriptor w.
+func (s SourceSet) ByWidth(w int64) (ImageSource, bool) {
+	for _, src := range s {
+		if src.Width != nil && *src.Width == w {
+			return src, true
+		}
+	}
+	return ImageSource{}, false
+}
+
+// ByDensity returns the first candidate with density descriptor d.
+func (s SourceSet) ByDensity(d float64) (ImageSource, bool) {
+	for _, src := range s {
+		if src.Density != nil && *src.Density == d {
+			return src, true
+		}
+	}
+	return ImageSource{}, false
+}
+
+// ByURL returns the first candidate with the given URL.
+func (s SourceSet) ByURL(url string) (ImageSource, bool) {
+	for _, src := range s {
+		if src.URL == url {
+			return src, true
+		}
+	}
+	return ImageSource{}, false
+}
+
+// ToWidthMap returns the candidates with a width descriptor keyed by width.
+// When several candidates share a width, the first one is kept.
+func (s SourceSet) ToWidthMap() map[int64]ImageSource {
+	m := map[int64]ImageSource{}
+	for _, src := range s {
+		if src.Width == nil {
+			continue
+		}
+		if _, ok := m[*src.Width]; !ok {
+			m[*src.Width] = src
+		}
+	}
+	return m
+}
+
+// ToDensityMap returns the candidates with a density descriptor keyed by
+// density. When several candidates share a density, the first one is kept.
+func (s SourceSet) ToDensityMap() map[float64]ImageSource {
+	m := map[float64]ImageSource{}
+	for _, src := range s {
+		if src.Density == nil {
+			continue
+		}
+		if _, ok := m[*src.Density]; !ok {
+			m[*src.Density] = src
+		}
+	}
+	return m
+}
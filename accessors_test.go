@@ -0,0 +1,92 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_accessors(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantURLs      []string
+		wantWidths    []int64
+		wantHeights   []int64
+		wantDensities []float64
+	}{
+		{
+			name:          "Empty",
+			input:         "",
+			wantURLs:      []string{},
+			wantWidths:    []int64{},
+			wantHeights:   []int64{},
+			wantDensities: []float64{},
+		},
+		{
+			name:          "Densities",
+			input:         "a.png, b.png 1.5x, c.png 2x",
+			wantURLs:      []string{"a.png", "b.png", "c.png"},
+			wantWidths:    []int64{},
+			wantHeights:   []int64{},
+			wantDensities: []float64{1.5, 2},
+		},
+		{
+			name:          "Widths and heights",
+			input:         "a.png 320w 200h, b.png 640w",
+			wantURLs:      []string{"a.png", "b.png"},
+			wantWidths:    []int64{320, 640},
+			wantHeights:   []int64{200},
+			wantDensities: []float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := Parse(tt.input)
+			if got := set.URLs(); !reflect.DeepEqual(got, tt.wantURLs) {
+				t.Errorf("%q. URLs() = %v, want %v", tt.name, got, tt.wantURLs)
+			}
+			if got := set.Widths(); !reflect.DeepEqual(got, tt.wantWidths) {
+				t.Errorf("%q. Widths() = %v, want %v", tt.name, got, tt.wantWidths)
+			}
+			if got := set.Heights(); !reflect.DeepEqual(got, tt.wantHeights) {
+				t.Errorf("%q. Heights() = %v, want %v", tt.name, got, tt.wantHeights)
+			}
+			if got := set.Densities(); !reflect.DeepEqual(got, tt.wantDensities) {
+				t.Errorf("%q. Densities() = %v, want %v", tt.name, got, tt.wantDensities)
+			}
+		})
+	}
+}
+
+func Test_lookup(t *testing.T) {
+	set := Parse("a.png 320w, b.png 640w, c.png 2x, d.png 640w")
+
+	if got, ok := set.ByWidth(640); !ok || got.URL != "b.png" {
+		t.Errorf("ByWidth(640) = %v, %v, want b.png, true", got, ok)
+	}
+	if _, ok := set.ByWidth(100); ok {
+		t.Errorf("ByWidth(100) found a candidate, want none")
+	}
+	if got, ok := set.ByDensity(2); !ok || got.URL != "c.png" {
+		t.Errorf("ByDensity(2) = %v, %v, want c.png, true", got, ok)
+	}
+	if _, ok := set.ByDensity(1); ok {
+		t.Errorf("ByDensity(1) found a candidate, want none")
+	}
+	if got, ok := set.ByURL("d.png"); !ok || *got.Width != 640 {
+		t.Errorf("ByURL(d.png) = %v, %v, want 640w, true", got, ok)
+	}
+	if _, ok := set.ByURL("e.png"); ok {
+		t.Errorf("ByURL(e.png) found a candidate, want none")
+	}
+
+	widths := set.ToWidthMap()
+	if len(widths) != 2 || widths[320].URL != "a.png" || widths[640].URL != "b.png" {
+		t.Errorf("ToWidthMap() = %v", widths)
+	}
+	densities := set.ToDensityMap()
+	if len(densities) != 1 || densities[2].URL != "c.png" {
+		t.Errorf("ToDensityMap() = %v", densities)
+	}
+}
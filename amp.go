@@ -0,0 +1,44 @@
+package srcset
+
+import "fmt"
+
+// AMPMaxCandidates is the largest number of candidates ValidateAMP accepts.
+const AMPMaxCandidates = 20
+
+// ValidateAMP checks s against the rules of Validate, and the stricter rules
+// of AMP pages:
+//
+//   - The set must not be empty, and may have at most AMPMaxCandidates
+//     candidates.
+//   - Height descriptors, custom descriptors and unknown descriptors are not
+//     allowed.
+func ValidateAMP(s SourceSet) []Violation {
+	if len(s) == 0 {
+		return []Violation{{Message: "empty srcset", Err: ErrEmptySet}}
+	}
+
+	violations := s.Validate()
+	if len(s) > AMPMaxCandidates {
+		violations = append(violations, Violation{
+			Offset:  s[AMPMaxCandidates].Offset,
+			URL:     s[AMPMaxCandidates].URL,
+			Message: fmt.Sprintf("more than %d candidates", AMPMaxCandidates),
+			Err:     ErrCandidateLimit,
+		})
+	}
+
+	for _, src := range s {
+		report := func(message string) {
+			violations = append(violations, Violation{Offset: src.Offset, URL: src.URL, Message: message, Err: ErrInvalidDescriptor})
+		}
+
+		if src.Height != nil {
+			report("height descriptor not allowed in AMP")
+		}
+		if len(src.Extensions) > 0 || len(src.RawDescriptors) > 0 {
+			report("unknown descriptor not allowed in AMP")
+		}
+	}
+
+	return violations
+}
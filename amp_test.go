@@ -0,0 +1,67 @@
+package srcset
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ValidateAMP(t *testing.T) {
+	var many []string
+	for i := 0; i <= AMPMaxCandidates; i++ {
+		many = append(many, "a.png "+formatFloat(float64(i+1))+"x")
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  []string
+	}{
+		{
+			name:  "Valid widths",
+			input: "a.png 320w, b.png 640w",
+		},
+		{
+			name:  "Valid densities",
+			input: "a.png, b.png 2x",
+		},
+		{
+			name: "Empty",
+			want: []string{" at offset 0: empty srcset"},
+		},
+		{
+			name:  "Mixed descriptors",
+			input: "a.png 320w, b.png 2x",
+			want:  []string{"b.png at offset 12: width descriptors mixed with other candidates"},
+		},
+		{
+			name:  "Height",
+			input: "a.png 320w 200h",
+			want:  []string{"a.png at offset 0: height descriptor not allowed in AMP"},
+		},
+		{
+			name:  "Custom descriptor",
+			input: "a.png 1x 80q",
+			opts:  []Option{WithDescriptorHandler('q', quality)},
+			want:  []string{"a.png at offset 0: unknown descriptor not allowed in AMP"},
+		},
+		{
+			name:  "Too many candidates",
+			input: strings.Join(many, ", "),
+			want:  []string{"a.png at offset 211: more than 20 candidates"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range ValidateAMP(Parse(tt.input, tt.opts...)) {
+				got = append(got, v.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ValidateAMP() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package srcset
+
+import (
+	"math"
+	"sort"
+)
+
+// AnalysisOptions controls the analysis of a set.
+type AnalysisOptions struct {
+	// Viewports are the viewport widths in CSS pixels that are analyzed, in
+	// increasing order.
+	Viewports []float64
+	// DPRs are the device pixel ratios that are analyzed.
+	DPRs []float64
+	// MaxUpscale is the factor by which the picked candidate may be
+	// upscaled before it is reported as a gap.
+	MaxUpscale float64
+	// Epsilon is the relative difference below which the densities of two
+	// candidates are reported as redundant.
+	Epsilon float64
+}
+
+// DefaultAnalysisOptions analyzes common viewport widths at 1x, 2x and 3x,
+// tolerating 20% upscaling and reporting densities less than 10% apart.
+var DefaultAnalysisOptions = AnalysisOptions{
+	Viewports:  []float64{320, 375, 414, 768, 1024, 1280, 1440, 1920, 2560},
+	DPRs:       []float64{1, 2, 3},
+	MaxUpscale: 1.2,
+	Epsilon:    0.1,
+}
+
+// Gap is a range of viewport widths in which a browser would pick a
+// candidate that is upscaled by more than the tolerated factor.
+type Gap struct {
+	DPR         float64
+	MinViewport float64
+	MaxViewport float64
+	Source      ImageSource
+	Upscale     float64 // the largest upscaling factor in the range
+}
+
+// Redundancy is a pair of candidates with nearly the same density.
+type Redundancy struct {
+	A, B ImageSource
+}
+
+// Analysis is the result of Analyze.
+type Analysis struct {
+	Gaps         []Gap
+	Redundancies []Redundancy
+}
+
+// Analyze analyzes s with DefaultAnalysisOptions. See AnalyzeWith.
+func Analyze(s SourceSet, sizes SizeList) Analysis {
+	return AnalyzeWith(s, sizes, DefaultAnalysisOptions)
+}
+
+// AnalyzeWith reports the gaps of s, where the candidate a browser would
+// pick is significantly upscaled for lack of a larger one, and its
+// redundancies, where candidates are so close in density that one of them is
+// unlikely to be picked. Width candidates are compared by width, since the
+// ratio of their densities does not depend on the source size.
+func AnalyzeWith(s SourceSet, sizes SizeList, opts AnalysisOptions) Analysis {
+	return Analysis{
+		Gaps:         s.gaps(sizes, opts),
+		Redundancies: s.redundancies(opts.Epsilon),
+	}
+}
+
+func (s SourceSet) gaps(sizes SizeList, opts AnalysisOptions) []Gap {
+	var gaps []Gap
+	for _, dpr := range opts.DPRs {
+		current := -1 // index of the gap of the previous viewport, if any
+		for _, vw := range opts.Viewports {
+			src, ok := s.BestForViewport(vw, dpr, sizes)
+			if !ok {
+				current = -1
+				continue
+			}
+			d, ok := src.Candidate().effectiveDensity(sizes.Evaluate(vw))
+			if !ok || d <= 0 || dpr/d <= opts.MaxUpscale {
+				current = -1
+				continue
+			}
+
+			upscale := dpr / d
+			if current >= 0 && gaps[current].Source.Equal(src) {
+				gaps[current].MaxViewport = vw
+				gaps[current].Upscale = math.Max(gaps[current].Upscale, upscale)
+				continue
+			}
+			gaps = append(gaps, Gap{DPR: dpr, MinViewport: vw, MaxViewport: vw, Source: src, Upscale: upscale})
+			current = len(gaps) - 1
+		}
+	}
+	return gaps
+}
+
+func (s SourceSet) redundancies(epsilon float64) []Redundancy {
+	var widths, densities []ImageSource
+	for _, src := range s {
+		if src.Candidate().HasWidth {
+			widths = append(widths, src)
+		} else {
+			densities = append(densities, src)
+		}
+	}
+
+	var redundancies []Redundancy
+	for _, group := range [][]ImageSource{widths, densities} {
+		value := func(src ImageSource) float64 {
+			c := src.Candidate()
+			if c.HasWidth {
+				return float64(c.Width)
+			}
+			return c.density()
+		}
+		sort.SliceStable(group, func(i, j int) bool { return value(group[i]) < value(group[j]) })
+
+		for i := 1; i < len(group); i++ {
+			a, b := value(group[i-1]), value(group[i])
+			if b > 0 && (b-a)/b < epsilon {
+				redundancies = append(redundancies, Redundancy{A: group[i-1], B: group[i]})
+			}
+		}
+	}
+	return redundancies
+}
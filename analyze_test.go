@@ -0,0 +1,80 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Analyze(t *testing.T) {
+	opts := AnalysisOptions{
+		Viewports:  []float64{400, 800, 1200, 1600},
+		DPRs:       []float64{1, 2},
+		MaxUpscale: 1.2,
+		Epsilon:    0.1,
+	}
+
+	// Candidates are referred to by their index in the input.
+	type gap struct {
+		dpr, min, max float64
+		source        int
+		upscale       float64
+	}
+	tests := []struct {
+		name         string
+		input        string
+		sizes        string
+		gaps         []gap
+		redundancies [][2]int
+	}{
+		{
+			name:  "Full coverage",
+			input: "a.png 400w, b.png 800w, c.png 1600w, d.png 3200w",
+			sizes: "100vw",
+		},
+		{
+			name:  "Gaps",
+			input: "a.png 400w, b.png 800w",
+			sizes: "100vw",
+			gaps: []gap{
+				{dpr: 1, min: 1200, max: 1600, source: 1, upscale: 2},
+				{dpr: 2, min: 800, max: 1600, source: 1, upscale: 4},
+			},
+		},
+		{
+			name:         "Redundant widths",
+			input:        "a.png 1000w, b.png 950w, c.png 2000w",
+			sizes:        "25vw",
+			redundancies: [][2]int{{1, 0}},
+		},
+		{
+			name:         "Redundant densities",
+			input:        "a.png, b.png 1.05x, c.png 2x, d.png 3x",
+			redundancies: [][2]int{{0, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := Parse(tt.input)
+
+			var want Analysis
+			for _, g := range tt.gaps {
+				want.Gaps = append(want.Gaps, Gap{DPR: g.dpr, MinViewport: g.min, MaxViewport: g.max, Source: set[g.source], Upscale: g.upscale})
+			}
+			for _, r := range tt.redundancies {
+				want.Redundancies = append(want.Redundancies, Redundancy{A: set[r[0]], B: set[r[1]]})
+			}
+
+			if got := AnalyzeWith(set, ParseSizes(tt.sizes), opts); !reflect.DeepEqual(got, want) {
+				t.Errorf("%q. AnalyzeWith() = %+v, want %+v", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func Test_Analyze_defaults(t *testing.T) {
+	got := Analyze(Parse("a.png 1x"), nil)
+	if len(got.Gaps) != 2 || got.Gaps[0].DPR != 2 || got.Gaps[1].DPR != 3 {
+		t.Errorf("Analyze() gaps = %+v, want gaps at 2x and 3x", got.Gaps)
+	}
+}
@@ -0,0 +1,105 @@
+package srcset
+
+// Arena holds the memory of the candidates parsed with WithArena, so that
+// parsing many attributes does not allocate per candidate. The zero value is
+// ready to use. An Arena is not safe for concurrent use.
+//
+// Sets parsed into an Arena, including their descriptor values, remain valid
+// until Reset is called; after that they must no longer be used, as their
+// memory is reused. Use Clone to keep a set beyond a Reset.
+type Arena struct {
+	sources  []ImageSource
+	ints     []int64
+	floats   []float64
+	overflow int // candidates parsed outside the slab since the last Reset
+}
+
+// arenaChunk is the minimum number of values allocated at once.
+const arenaChunk = 256
+
+// WithArena makes the parser allocate the candidates and descriptor values
+// of its results from a. A Parser configured this way must not be used
+// concurrently, and must not be wrapped in a CachedParser.
+func WithArena(a *Arena) Option {
+	return func(c *config) {
+		c.arena = a
+	}
+}
+
+// Reset releases everything parsed into a since the last Reset at once, and
+// keeps the memory for reuse.
+func (a *Arena) Reset() {
+	for i := range a.sources {
+		// Drop the references to the input, so that it can be collected.
+		a.sources[i] = ImageSource{}
+	}
+	a.sources = a.sources[:0]
+	a.ints = a.ints[:0]
+	a.floats = a.floats[:0]
+	if a.overflow > 0 {
+		// Grow the slab, so that the next document fits.
+		a.sources = make([]ImageSource, 0, cap(a.sources)+a.overflow)
+		a.overflow = 0
+	}
+}
+
+// sourceSet returns an empty set backed by the free part of the slab.
+func (a *Arena) sourceSet() SourceSet {
+	if cap(a.sources)-len(a.sources) < arenaChunk/16 {
+		a.sources = make([]ImageSource, 0, grow(cap(a.sources)))
+	}
+	return a.sources[len(a.sources):len(a.sources)]
+}
+
+// commit marks the candidates of s, which was obtained from sourceSet, as
+// used, and returns s with its capacity limited to its length, so that
+// appending to it does not overwrite the next set.
+func (a *Arena) commit(s SourceSet) SourceSet {
+	free := a.sources[len(a.sources):cap(a.sources)]
+	if len(s) > 0 && len(s) <= len(free) && &s[0] == &free[0] {
+		a.sources = a.sources[:len(a.sources)+len(s)]
+	} else {
+		a.overflow += len(s)
+	}
+	return s[:len(s):len(s)]
+}
+
+func (a *Arena) int64(v int64) *int64 {
+	if len(a.ints) == cap(a.ints) {
+		a.ints = make([]int64, 0, grow(cap(a.ints)))
+	}
+	a.ints = append(a.ints, v)
+	return &a.ints[len(a.ints)-1]
+}
+
+func (a *Arena) float64(v float64) *float64 {
+	if len(a.floats) == cap(a.floats) {
+		a.floats = make([]float64, 0, grow(cap(a.floats)))
+	}
+	a.floats = append(a.floats, v)
+	return &a.floats[len(a.floats)-1]
+}
+
+// imageSource is like Candidate.ImageSource, but allocates the descriptor
+// values from a.
+func (a *Arena) imageSource(c Candidate) ImageSource {
+	src := ImageSource{URL: c.URL, Offset: c.Offset}
+	if c.HasWidth {
+		src.Width = a.int64(c.Width)
+	}
+	if c.HasHeight {
+		src.Height = a.int64(c.Height)
+	}
+	if c.HasDensity {
+		src.Density = a.float64(c.Density)
+	}
+	return src
+}
+
+// grow returns the capacity of the chunk following one of capacity n.
+func grow(n int) int {
+	if n < arenaChunk {
+		return arenaChunk
+	}
+	return 2 * n
+}
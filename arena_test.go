@@ -0,0 +1,88 @@
+package srcset
+
+import "testing"
+
+func Test_Arena(t *testing.T) {
+	const input = "a.png 320w, b.png 640w 480h, c.png 2x"
+
+	var a Arena
+	p := NewParser(WithArena(&a))
+	first := p.Parse(input)
+	second := p.Parse("d.png 1.5x")
+
+	if want := Parse(input); !first.Equal(want) {
+		t.Errorf("Parse() = %v, want %v", first, want)
+	}
+	if want := Parse("d.png 1.5x"); !second.Equal(want) {
+		t.Errorf("Parse() = %v, want %v", second, want)
+	}
+	if cap(first) != len(first) {
+		t.Errorf("cap() = %d, want %d", cap(first), len(first))
+	}
+
+	// Appending must not overwrite the next set in the arena.
+	_ = append(first, ImageSource{URL: "e.png"})
+	if second[0].URL != "d.png" {
+		t.Errorf("URL = %q after append, want %q", second[0].URL, "d.png")
+	}
+
+	kept := first.Clone()
+	a.Reset()
+	p.Parse("f.png 100w, g.png 200w, h.png 300w")
+	if want := Parse(input); !kept.Equal(want) {
+		t.Errorf("Clone() = %v after Reset, want %v", kept, want)
+	}
+}
+
+func Test_Arena_Overflow(t *testing.T) {
+	var (
+		a     Arena
+		input = "a.png 1x"
+	)
+	for n := 2; n <= 2*arenaChunk; n++ {
+		input += ", a.png " + formatFloat(float64(n)) + "x"
+	}
+
+	p := NewParser(WithArena(&a))
+	if got := p.Parse(input); len(got) != 2*arenaChunk {
+		t.Fatalf("len() = %d, want %d", len(got), 2*arenaChunk)
+	}
+	a.Reset()
+	if cap(a.sources) < 2*arenaChunk {
+		t.Errorf("cap() = %d after Reset, want at least %d", cap(a.sources), 2*arenaChunk)
+	}
+	if got := p.Parse(input); len(got) != 2*arenaChunk || *got[2*arenaChunk-1].Density != 2*arenaChunk {
+		t.Errorf("Parse() = %v after Reset", got)
+	}
+}
+
+func Test_Arena_Allocs(t *testing.T) {
+	const input = "a.png 320w, b.png 640w 480h, c.png 800w, d.png 1024w"
+
+	var a Arena
+	p := NewParser(WithArena(&a))
+	p.Parse(input)
+	a.Reset()
+
+	withArena := testing.AllocsPerRun(100, func() {
+		p.Parse(input)
+		a.Reset()
+	})
+	without := testing.AllocsPerRun(100, func() {
+		Parse(input)
+	})
+	if withArena >= without {
+		t.Errorf("allocations with arena = %v, want fewer than %v", withArena, without)
+	}
+}
+
+func BenchmarkParseArena(b *testing.B) {
+	const input = `elva-fairy-320w.jpg 320w, elva-fairy-480w.jpg 480w, elva-fairy-800w.jpg 800w 600h, data:,a ( , data:,b 1x, ), data:,c`
+	var a Arena
+	p := NewParser(WithArena(&a))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		p.Parse(input)
+		a.Reset()
+	}
+}
@@ -0,0 +1,46 @@
+package srcset
+
+import "math"
+
+// AspectRatio returns the ratio of width to height of src. It reports false
+// unless src has both a width and a height descriptor.
+func (src ImageSource) AspectRatio() (float64, bool) {
+	return src.Candidate().AspectRatio()
+}
+
+// AspectRatio returns the ratio of width to height of c. It reports false
+// unless c has both a width and a height descriptor.
+func (c Candidate) AspectRatio() (float64, bool) {
+	if !c.HasWidth || !c.HasHeight || c.Height == 0 {
+		return 0, false
+	}
+	return float64(c.Width) / float64(c.Height), true
+}
+
+// InconsistentAspectRatios returns the candidates whose aspect ratio differs
+// from that of the first candidate with both width and height descriptors by
+// more than tolerance, relative to that ratio. Candidates without an aspect
+// ratio are ignored.
+func (s SourceSet) InconsistentAspectRatios(tolerance float64) []ImageSource {
+	var (
+		inconsistent []ImageSource
+		ref          float64
+		hasRef       bool
+	)
+
+	for _, src := range s {
+		ratio, ok := src.AspectRatio()
+		if !ok {
+			continue
+		}
+		if !hasRef {
+			ref, hasRef = ratio, true
+			continue
+		}
+		if math.Abs(ratio-ref)/ref > tolerance {
+			inconsistent = append(inconsistent, src)
+		}
+	}
+
+	return inconsistent
+}
@@ -0,0 +1,54 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_AspectRatio(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    ImageSource
+		want   float64
+		wantOK bool
+	}{
+		{name: "Width and height", src: ImageSource{Width: i(400), Height: i(300)}, want: 4.0 / 3, wantOK: true},
+		{name: "Width only", src: ImageSource{Width: i(400)}},
+		{name: "Density", src: ImageSource{Density: fl(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.src.AspectRatio()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("%q. AspectRatio() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_InconsistentAspectRatios(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		tolerance float64
+		want      []string
+	}{
+		{name: "Consistent", input: "a.jpg 400w 300h, b.jpg 800w 600h", tolerance: 0.01},
+		{name: "Rounding", input: "a.jpg 400w 300h, b.jpg 333w 250h", tolerance: 0.01},
+		{name: "Inconsistent", input: "a.jpg 400w 300h, b.jpg 800w 450h, c.jpg 1600w 1200h", tolerance: 0.01, want: []string{"b.jpg"}},
+		{name: "Missing heights", input: "a.jpg 400w, b.jpg 800w 450h, c.jpg 1600w 1200h", tolerance: 0.01, want: []string{"c.jpg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, src := range Parse(tt.input).InconsistentAspectRatios(tt.tolerance) {
+				got = append(got, src.URL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. InconsistentAspectRatios() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package srcset
+
+// AttrString serializes the SourceSet like String, but guarantees that the
+// result is safe to embed in a double-quoted HTML attribute and parses back
+// into the same candidates. See AppendAttr.
+func (s SourceSet) AttrString() string {
+	return string(s.AppendAttr(make([]byte, 0, len(s)*32)))
+}
+
+// AppendAttr appends the serialized SourceSet to dst like AppendEncoded, but
+// also escapes the characters that are special in HTML attributes as
+// character references.
+func (s SourceSet) AppendAttr(dst []byte) []byte {
+	for i, src := range s {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = appendAttrURL(dst, src.URL)
+		dst = appendAttrEscaped(dst, string(appendDescriptors(nil, src)))
+	}
+	return dst
+}
+
+func appendAttrURL(dst []byte, url string) []byte {
+	for i := 0; i < len(url); i++ {
+		c := url[i]
+		if unsafeURLByte(url, i) {
+			dst = appendPercentEncoded(dst, c)
+		} else {
+			dst = appendAttrEscaped(dst, url[i:i+1])
+		}
+	}
+	return dst
+}
+
+func appendPercentEncoded(dst []byte, c byte) []byte {
+	const hex = "0123456789ABCDEF"
+	return append(dst, '%', hex[c>>4], hex[c&0xf])
+}
+
+func appendAttrEscaped(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '&':
+			dst = append(dst, "&amp;"...)
+		case '"':
+			dst = append(dst, "&#34;"...)
+		case '\'':
+			dst = append(dst, "&#39;"...)
+		case '<':
+			dst = append(dst, "&lt;"...)
+		case '>':
+			dst = append(dst, "&gt;"...)
+		default:
+			dst = append(dst, s[i])
+		}
+	}
+	return dst
+}
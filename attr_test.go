@@ -0,0 +1,57 @@
+package srcset
+
+import (
+	"html"
+	"testing"
+)
+
+func Test_AttrString(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+		want string
+	}{
+		{
+			name: "Plain",
+			set:  Parse("a.png 1x, b.png 2x"),
+			want: "a.png 1x, b.png 2x",
+		},
+		{
+			name: "Ampersands and quotes",
+			set:  Parse(`a.png?w=1&h="2" 1x`),
+			want: "a.png?w=1&amp;h=&#34;2&#34; 1x",
+		},
+		{
+			name: "Whitespace in URL",
+			set:  SourceSet{{URL: "my image.png", Width: i(100)}},
+			want: "my%20image.png 100w",
+		},
+		{
+			name: "Leading and trailing commas",
+			set:  SourceSet{{URL: ",a,b,"}},
+			want: "%2Ca,b%2C",
+		},
+		{
+			name: "Data URL",
+			set:  Parse("data:,a 1x"),
+			want: "data:,a 1x",
+		},
+		{
+			name: "Markup in descriptors",
+			set:  SourceSet{{URL: "a.png", RawDescriptors: []string{"<b>"}}},
+			want: "a.png &lt;b&gt;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.set.AttrString()
+			if got != tt.want {
+				t.Errorf("%q. AttrString() = %q, want %q", tt.name, got, tt.want)
+			}
+			if reparsed := Parse(html.UnescapeString(got), WithForwardCompatibility()); len(reparsed) != len(tt.set) {
+				t.Errorf("%q. AttrString() = %q parses into %d candidates, want %d", tt.name, got, len(reparsed), len(tt.set))
+			}
+		})
+	}
+}
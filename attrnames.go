@@ -0,0 +1,46 @@
+package srcset
+
+import "strings"
+
+// AttributeNames holds the names of the HTML attributes that hold the parts
+// of a responsive image. Names are matched case-insensitively.
+type AttributeNames struct {
+	Srcset []string
+	Sizes  []string
+	Src    []string
+}
+
+var (
+	// DefaultAttributeNames are the standard attributes of img, source and
+	// link elements.
+	DefaultAttributeNames = AttributeNames{
+		Srcset: []string{"srcset", "imagesrcset"},
+		Sizes:  []string{"sizes", "imagesizes"},
+		Src:    []string{"src"},
+	}
+
+	// LazyAttributeNames add the data-srcset, data-sizes and data-src
+	// attributes used by lazy-loading libraries such as lazysizes to the
+	// standard attributes.
+	LazyAttributeNames = AttributeNames{
+		Srcset: []string{"srcset", "imagesrcset", "data-srcset"},
+		Sizes:  []string{"sizes", "imagesizes", "data-sizes"},
+		Src:    []string{"src", "data-src"},
+	}
+)
+
+func (c *config) attributeNames() AttributeNames {
+	if c.attrNames != nil {
+		return *c.attrNames
+	}
+	return DefaultAttributeNames
+}
+
+// nameSet returns the lowercase names as a set.
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	return set
+}
@@ -0,0 +1,158 @@
+// Package audit reports on the responsive images of an HTML document,
+// combining the validation, lint and analysis of the srcset package with
+// optional network checks.
+package audit
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/lukasbob/srcset"
+	"github.com/lukasbob/srcset/check"
+)
+
+// Options controls an audit.
+type Options struct {
+	// ParseOptions are used to parse the attributes, and configure which
+	// attributes are audited. See srcset.WithAttributeNames. A warning
+	// handler among them is called in addition to collecting the warnings
+	// in the report.
+	ParseOptions []srcset.Option
+
+	// Analysis controls the coverage analysis. It defaults to
+	// srcset.DefaultAnalysisOptions.
+	Analysis *srcset.AnalysisOptions
+
+	// Checker, if set, checks every candidate over the network.
+	Checker *check.Checker
+	// BaseURL resolves relative candidate URLs for network checks.
+	BaseURL string
+}
+
+// Report is the result of an audit.
+type Report struct {
+	Images []Image
+}
+
+// Image is the report on a single img, source or link element.
+type Image struct {
+	Element string // the tag name
+	Offset  int    // byte offset of the tag in the document
+
+	Img        srcset.ImgElement
+	Warnings   []srcset.Warning   // problems found while parsing
+	Violations []srcset.Violation // see srcset.SourceSet.Validate
+	Lint       []srcset.Violation // see srcset.SourceSet.Lint
+	Analysis   srcset.Analysis
+	Checks     []check.Result // nil without Options.Checker
+}
+
+// Problems reports whether any warnings, violations, lint findings, gaps,
+// redundancies or failed checks were found.
+func (img Image) Problems() bool {
+	if len(img.Warnings) > 0 || len(img.Violations) > 0 || len(img.Lint) > 0 ||
+		len(img.Analysis.Gaps) > 0 || len(img.Analysis.Redundancies) > 0 {
+		return true
+	}
+	for _, res := range img.Checks {
+		if !res.OK() || res.Mismatch {
+			return true
+		}
+	}
+	return false
+}
+
+// Audit reports on every img, source and link element of the HTML document
+// read from r that has a srcset, or, for img elements, a src attribute.
+func Audit(ctx context.Context, r io.Reader, opts Options) (*Report, error) {
+	var base *url.URL
+	if opts.BaseURL != "" {
+		var err error
+		if base, err = url.Parse(opts.BaseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	analysis := srcset.DefaultAnalysisOptions
+	if opts.Analysis != nil {
+		analysis = *opts.Analysis
+	}
+
+	var (
+		report = &Report{}
+		z      = html.NewTokenizer(r)
+		offset = 0
+		warn   = srcset.WarningHandlerOf(opts.ParseOptions...)
+	)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			return report, nil
+		}
+		pos := offset
+		offset += len(z.Raw())
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		tok := z.Token()
+		switch tok.Data {
+		case "img", "source", "link":
+		default:
+			continue
+		}
+
+		attrs := make(map[string]string, len(tok.Attr))
+		for _, attr := range tok.Attr {
+			if _, ok := attrs[attr.Key]; !ok {
+				attrs[attr.Key] = attr.Val
+			}
+		}
+
+		img := Image{Element: tok.Data, Offset: pos}
+		parseOpts := append(append([]srcset.Option(nil), opts.ParseOptions...), srcset.WithWarningHandler(func(w srcset.Warning) {
+			if warn != nil {
+				warn(w)
+			}
+			img.Warnings = append(img.Warnings, w)
+		}))
+		img.Img = srcset.ParseImgElement(attrs, parseOpts...)
+		if img.Img.Srcset == nil && (tok.Data != "img" || img.Img.Src == "") {
+			continue
+		}
+
+		candidates := img.Img.Candidates()
+		img.Violations = img.Img.Srcset.Validate()
+		img.Lint = img.Img.Srcset.Lint()
+		img.Analysis = srcset.AnalyzeWith(candidates, img.Img.Sizes, analysis)
+		if opts.Checker != nil {
+			img.Checks = opts.Checker.Check(ctx, resolve(candidates, base))
+		}
+
+		report.Images = append(report.Images, img)
+	}
+}
+
+// resolve returns a copy of set with the URLs resolved against base.
+func resolve(set srcset.SourceSet, base *url.URL) srcset.SourceSet {
+	if base == nil {
+		return set
+	}
+	resolved := set.Clone()
+	for i, src := range resolved {
+		if strings.HasPrefix(strings.ToLower(src.URL), "data:") {
+			continue
+		}
+		if u, err := base.Parse(src.URL); err == nil {
+			resolved[i].URL = u.String()
+		}
+	}
+	return resolved
+}
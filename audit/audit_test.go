@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lukasbob/srcset"
+	"github.com/lukasbob/srcset/check"
+)
+
+const page = `<!DOCTYPE html>
+<html><head><link rel="stylesheet" href="a.css"></head>
+<body>
+<img src="logo.png" alt="">
+<picture>
+<source type="image/webp" srcset="/a.webp 400w, /b.webp 800w" sizes="100vw">
+<img src="/a.jpg" srcset="/a.jpg 1x, /b.jpg 1x, //cdn.example.com/c.jpg 2x,, d.jpg 1q">
+</picture>
+<p>Text</p>
+</body></html>`
+
+func Test_Audit(t *testing.T) {
+	report, err := Audit(context.Background(), strings.NewReader(page), Options{})
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	if len(report.Images) != 3 {
+		t.Fatalf("Audit() found %d images, want 3", len(report.Images))
+	}
+
+	tests := []struct {
+		name       string
+		element    string
+		offset     int
+		violations int
+		lint       int
+		warnings   int
+	}{
+		{name: "Plain img", element: "img", offset: strings.Index(page, `<img src="logo.png"`)},
+		{name: "Source", element: "source", offset: strings.Index(page, "<source")},
+		{name: "Img with problems", element: "img", offset: strings.Index(page, `<img src="/a.jpg"`), violations: 1, lint: 1, warnings: 2},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := report.Images[i]
+			if got.Element != tt.element || got.Offset != tt.offset {
+				t.Errorf("%q. element = %s at %d, want %s at %d", tt.name, got.Element, got.Offset, tt.element, tt.offset)
+			}
+			if len(got.Violations) != tt.violations {
+				t.Errorf("%q. Violations = %v, want %d", tt.name, got.Violations, tt.violations)
+			}
+			if len(got.Lint) != tt.lint {
+				t.Errorf("%q. Lint = %v, want %d", tt.name, got.Lint, tt.lint)
+			}
+			if len(got.Warnings) != tt.warnings {
+				t.Errorf("%q. Warnings = %v, want %d", tt.name, got.Warnings, tt.warnings)
+			}
+			if got.Checks != nil {
+				t.Errorf("%q. Checks = %v, want none", tt.name, got.Checks)
+			}
+		})
+	}
+
+	if !report.Images[0].Problems() {
+		t.Error("Problems() = false for a 1x image, want gaps at higher densities")
+	}
+}
+
+func Test_Audit_checks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a.png" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := Options{
+		Checker:  &check.Checker{Client: srv.Client()},
+		BaseURL:  srv.URL + "/page/",
+		Analysis: &srcset.AnalysisOptions{},
+	}
+	report, err := Audit(context.Background(), strings.NewReader(`<img srcset="/a.png 1x, b.png 2x">`), opts)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	checks := report.Images[0].Checks
+	if len(checks) != 2 || !checks[0].OK() || checks[1].StatusCode != http.StatusNotFound {
+		t.Errorf("Checks = %+v, want OK and not found", checks)
+	}
+	if got, want := checks[1].Source.URL, srv.URL+"/page/b.png"; got != want {
+		t.Errorf("Checks[1].Source.URL = %q, want %q", got, want)
+	}
+	if !report.Images[0].Problems() {
+		t.Error("Problems() = false, want true")
+	}
+}
+
+func Test_Audit_warningHandler(t *testing.T) {
+	var got []srcset.Warning
+	opts := Options{ParseOptions: []srcset.Option{srcset.WithWarningHandler(func(w srcset.Warning) {
+		got = append(got, w)
+	})}}
+	report, err := Audit(context.Background(), strings.NewReader(`<img srcset="a.png 1x, b.png 1q">`), opts)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	if len(report.Images) != 1 || len(report.Images[0].Warnings) != 1 {
+		t.Fatalf("Audit() images = %v, want one with a warning", report.Images)
+	}
+	if len(got) != 1 || got[0] != report.Images[0].Warnings[0] {
+		t.Errorf("handler got %v, want %v", got, report.Images[0].Warnings)
+	}
+}
+
+func Test_Audit_invalidBaseURL(t *testing.T) {
+	if _, err := Audit(context.Background(), strings.NewReader(""), Options{BaseURL: "http://[::1"}); err == nil {
+		t.Error("Audit() error = nil, want an error")
+	}
+}
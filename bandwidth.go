@@ -0,0 +1,60 @@
+package srcset
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ClientHints holds the network related client hints sent by a browser.
+type ClientHints struct {
+	SaveData bool   // the Save-Data header is "on"
+	ECT      string // the effective connection type: "slow-2g", "2g", "3g" or "4g"
+}
+
+// ClientHintsFromHeader reads the Save-Data and ECT client hints from h.
+func ClientHintsFromHeader(h http.Header) ClientHints {
+	saveData := strings.TrimSpace(strings.SplitN(h.Get("Save-Data"), ";", 2)[0])
+	return ClientHints{
+		SaveData: strings.EqualFold(saveData, "on"),
+		ECT:      strings.ToLower(strings.TrimSpace(h.Get("ECT"))),
+	}
+}
+
+// BandwidthPolicy caps the device pixel ratio used for selection when a
+// client asks to save data or is on a slow connection.
+type BandwidthPolicy struct {
+	// SaveDataMaxDPR caps the device pixel ratio when Save-Data is on. Zero
+	// means no cap.
+	SaveDataMaxDPR float64
+	// ECTMaxDPR caps the device pixel ratio per effective connection type.
+	// Connection types without an entry are not capped.
+	ECTMaxDPR map[string]float64
+}
+
+// DefaultBandwidthPolicy serves 1x images to clients that ask to save data
+// or are on 2G connections, and at most 1.5x images on 3G connections.
+var DefaultBandwidthPolicy = BandwidthPolicy{
+	SaveDataMaxDPR: 1,
+	ECTMaxDPR: map[string]float64{
+		"slow-2g": 1,
+		"2g":      1,
+		"3g":      1.5,
+	},
+}
+
+// CapDPR returns dpr, lowered to the caps that apply to hints.
+func (p BandwidthPolicy) CapDPR(dpr float64, hints ClientHints) float64 {
+	if hints.SaveData && p.SaveDataMaxDPR > 0 && dpr > p.SaveDataMaxDPR {
+		dpr = p.SaveDataMaxDPR
+	}
+	if max, ok := p.ECTMaxDPR[hints.ECT]; ok && max > 0 && dpr > max {
+		dpr = max
+	}
+	return dpr
+}
+
+// BestForClient is like BestForViewport, but lowers the device pixel ratio
+// according to policy and the client hints before picking a candidate.
+func (s SourceSet) BestForClient(viewportWidth, dpr float64, sizes SizeList, policy BandwidthPolicy, hints ClientHints) (ImageSource, bool) {
+	return s.BestForViewport(viewportWidth, policy.CapDPR(dpr, hints), sizes)
+}
@@ -0,0 +1,57 @@
+package srcset
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_ClientHintsFromHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   ClientHints
+	}{
+		{name: "None", header: http.Header{}, want: ClientHints{}},
+		{name: "Save-Data", header: http.Header{"Save-Data": {"On"}}, want: ClientHints{SaveData: true}},
+		{name: "Save-Data with parameters", header: http.Header{"Save-Data": {"on; foo=bar"}}, want: ClientHints{SaveData: true}},
+		{name: "Save-Data off", header: http.Header{"Save-Data": {"off"}}, want: ClientHints{}},
+		{name: "ECT", header: http.Header{"Ect": {"3G"}}, want: ClientHints{ECT: "3g"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClientHintsFromHeader(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ClientHintsFromHeader() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_BestForClient(t *testing.T) {
+	const widths = "s.jpg 320w, m.jpg 640w, l.jpg 1280w"
+
+	tests := []struct {
+		name   string
+		dpr    float64
+		policy BandwidthPolicy
+		hints  ClientHints
+		want   string
+	}{
+		{name: "No hints", dpr: 3, policy: DefaultBandwidthPolicy, want: "l.jpg"},
+		{name: "Save-Data", dpr: 3, policy: DefaultBandwidthPolicy, hints: ClientHints{SaveData: true}, want: "s.jpg"},
+		{name: "3G", dpr: 3, policy: DefaultBandwidthPolicy, hints: ClientHints{ECT: "3g"}, want: "m.jpg"},
+		{name: "4G", dpr: 3, policy: DefaultBandwidthPolicy, hints: ClientHints{ECT: "4g"}, want: "l.jpg"},
+		{name: "Zero policy", dpr: 3, hints: ClientHints{SaveData: true, ECT: "2g"}, want: "l.jpg"},
+		{name: "Cap above DPR", dpr: 1, policy: DefaultBandwidthPolicy, hints: ClientHints{ECT: "3g"}, want: "s.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := Parse(widths).BestForClient(320, tt.dpr, nil, tt.policy, tt.hints)
+			if got.URL != tt.want {
+				t.Errorf("%q. BestForClient() = %v, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package srcset
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParseAll parses every input concurrently, using at most workers goroutines,
+// and returns the results in the order of the inputs. If workers is zero or
+// less, runtime.GOMAXPROCS(0) goroutines are used. The warning handler and
+// StatsRecorder of the options are called from several goroutines at once,
+// so they must be safe for concurrent use.
+func ParseAll(inputs []string, workers int, opts ...Option) []SourceSet {
+	return NewParser(opts...).ParseAll(inputs, workers)
+}
+
+// ParseAll parses every input concurrently, using at most workers goroutines,
+// and returns the results in the order of the inputs. If workers is zero or
+// less, runtime.GOMAXPROCS(0) goroutines are used. The warning handler and
+// StatsRecorder of the parser are called from several goroutines at once,
+// so they must be safe for concurrent use.
+func (p *Parser) ParseAll(inputs []string, workers int) []SourceSet {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	var (
+		results = make([]SourceSet, len(inputs))
+		indices = make(chan int)
+		wg      sync.WaitGroup
+	)
+
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = p.Parse(inputs[i])
+			}
+		}()
+	}
+
+	for i := range inputs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,39 @@
+package srcset
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_ParseAll(t *testing.T) {
+	var inputs []string
+	for n := 0; n < 100; n++ {
+		inputs = append(inputs, fmt.Sprintf("a-%d.png 1x, b-%d.png %dw", n, n, n+1))
+	}
+
+	tests := []struct {
+		name    string
+		inputs  []string
+		workers int
+	}{
+		{name: "No inputs", inputs: nil, workers: 4},
+		{name: "Single worker", inputs: inputs, workers: 1},
+		{name: "Many workers", inputs: inputs, workers: 16},
+		{name: "Default workers", inputs: inputs, workers: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseAll(tt.inputs, tt.workers)
+			if len(got) != len(tt.inputs) {
+				t.Fatalf("%q. ParseAll() returned %d results, want %d", tt.name, len(got), len(tt.inputs))
+			}
+			for i, input := range tt.inputs {
+				if want := Parse(input); !reflect.DeepEqual(got[i], want) {
+					t.Errorf("%q. ParseAll()[%d] = %v, want %v", tt.name, i, got[i], want)
+				}
+			}
+		})
+	}
+}
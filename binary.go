@@ -0,0 +1,208 @@
+package srcset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const binaryVersion = 1
+
+const (
+	flagWidth = 1 << iota
+	flagHeight
+	flagDensity
+	flagExtensions
+	flagRawDescriptors
+)
+
+var errShortBuffer = errors.New("srcset: binary data is truncated")
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is compact
+// and versioned, and preserves every field of the candidates.
+func (s SourceSet) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64+len(s)*16)
+	buf = append(buf, binaryVersion)
+	buf = appendUvarint(buf, uint64(len(s)))
+
+	for _, src := range s {
+		var flags byte
+		if src.Width != nil {
+			flags |= flagWidth
+		}
+		if src.Height != nil {
+			flags |= flagHeight
+		}
+		if src.Density != nil {
+			flags |= flagDensity
+		}
+		if len(src.Extensions) > 0 {
+			flags |= flagExtensions
+		}
+		if len(src.RawDescriptors) > 0 {
+			flags |= flagRawDescriptors
+		}
+
+		buf = append(buf, flags)
+		buf = appendString(buf, src.URL)
+		buf = appendVarint(buf, int64(src.Offset))
+		if src.Width != nil {
+			buf = appendVarint(buf, *src.Width)
+		}
+		if src.Height != nil {
+			buf = appendVarint(buf, *src.Height)
+		}
+		if src.Density != nil {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(*src.Density))
+			buf = append(buf, b[:]...)
+		}
+		if len(src.Extensions) > 0 {
+			buf = appendUvarint(buf, uint64(len(src.Extensions)))
+			for _, key := range sortedKeys(src.Extensions) {
+				buf = appendString(buf, key)
+				buf = appendString(buf, src.Extensions[key])
+			}
+		}
+		if len(src.RawDescriptors) > 0 {
+			buf = appendUvarint(buf, uint64(len(src.RawDescriptors)))
+			for _, desc := range src.RawDescriptors {
+				buf = appendString(buf, desc)
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *SourceSet) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errShortBuffer
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("srcset: unsupported binary version %d", data[0])
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return errShortBuffer
+	}
+	data = data[n:]
+
+	set := make(SourceSet, 0, count)
+	for ; count > 0; count-- {
+		if len(data) == 0 {
+			return errShortBuffer
+		}
+		flags := data[0]
+		data = data[1:]
+
+		var (
+			src ImageSource
+			ok  bool
+		)
+		if src.URL, data, ok = readString(data); !ok {
+			return errShortBuffer
+		}
+
+		offset, n := binary.Varint(data)
+		if n <= 0 {
+			return errShortBuffer
+		}
+		src.Offset = int(offset)
+		data = data[n:]
+
+		if flags&flagWidth != 0 {
+			w, n := binary.Varint(data)
+			if n <= 0 {
+				return errShortBuffer
+			}
+			src.Width = &w
+			data = data[n:]
+		}
+		if flags&flagHeight != 0 {
+			h, n := binary.Varint(data)
+			if n <= 0 {
+				return errShortBuffer
+			}
+			src.Height = &h
+			data = data[n:]
+		}
+		if flags&flagDensity != 0 {
+			if len(data) < 8 {
+				return errShortBuffer
+			}
+			d := math.Float64frombits(binary.LittleEndian.Uint64(data))
+			src.Density = &d
+			data = data[8:]
+		}
+		if flags&flagExtensions != 0 {
+			count, n := binary.Uvarint(data)
+			if n <= 0 || count > uint64(len(data)) {
+				return errShortBuffer
+			}
+			data = data[n:]
+			src.Extensions = make(map[string]string, count)
+			for ; count > 0; count-- {
+				var key, value string
+				if key, data, ok = readString(data); !ok {
+					return errShortBuffer
+				}
+				if value, data, ok = readString(data); !ok {
+					return errShortBuffer
+				}
+				src.Extensions[key] = value
+			}
+		}
+		if flags&flagRawDescriptors != 0 {
+			count, n := binary.Uvarint(data)
+			if n <= 0 || count > uint64(len(data)) {
+				return errShortBuffer
+			}
+			data = data[n:]
+			src.RawDescriptors = make([]string, count)
+			for i := range src.RawDescriptors {
+				if src.RawDescriptors[i], data, ok = readString(data); !ok {
+					return errShortBuffer
+				}
+			}
+		}
+
+		set = append(set, src)
+	}
+
+	if len(data) != 0 {
+		return errors.New("srcset: trailing binary data")
+	}
+
+	*s = set
+	return nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutVarint(b[:], v)]...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// readString reads a length-prefixed string from data and returns it
+// together with the remaining data.
+func readString(data []byte) (string, []byte, bool) {
+	l, n := binary.Uvarint(data)
+	if n <= 0 || l > uint64(len(data)-n) {
+		return "", data, false
+	}
+	return string(data[n : n+int(l)]), data[n+int(l):], true
+}
@@ -0,0 +1,77 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_binaryEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+	}{
+		{
+			name: "Empty",
+			set:  SourceSet{},
+		},
+		{
+			name: "Densities",
+			set:  Parse("image-1x.png 1x, image-2x.png 2x, image-3x.png 3.5x"),
+		},
+		{
+			name: "Width and height",
+			set:  Parse("a.png 320w 200h, b.png 640w 400h"),
+		},
+		{
+			name: "Extensions",
+			set:  Parse("a.png 2x 80q, b.png 3x", WithDescriptorHandler('q', quality)),
+		},
+		{
+			name: "Raw descriptors",
+			set:  Parse("a.png 2x future(1), b.png 3x", WithForwardCompatibility()),
+		},
+		{
+			name: "URL only",
+			set:  Parse("data:,c"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.set.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%q. MarshalBinary() error = %v", tt.name, err)
+			}
+			var got SourceSet
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("%q. UnmarshalBinary() error = %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, tt.set) {
+				t.Errorf("%q. UnmarshalBinary() = %v, want %v", tt.name, got, tt.set)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalBinary_invalid(t *testing.T) {
+	data, _ := Parse("a.png 320w 200h, b.png 2x").MarshalBinary()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "Unknown version", data: []byte{99, 0}},
+		{name: "Truncated", data: data[:len(data)-3]},
+		{name: "Trailing data", data: append(append([]byte{}, data...), 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SourceSet
+			if err := got.UnmarshalBinary(tt.data); err == nil {
+				t.Errorf("%q. UnmarshalBinary() error = nil, want error", tt.name)
+			}
+		})
+	}
+}
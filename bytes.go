@@ -0,0 +1,132 @@
+package srcset
+
+import (
+	"math"
+	"sort"
+)
+
+// ByteModel predicts the transfer size of images from their dimensions.
+type ByteModel struct {
+	// BytesPerPixel is the average encoded size of a pixel. It is used
+	// when there are no Samples.
+	BytesPerPixel float64
+	// Samples are measured sizes of images of the set, such as those of a
+	// few candidates. When present, the bytes per pixel are interpolated
+	// between them by pixel count, instead of using BytesPerPixel.
+	Samples []ByteSample
+	// AspectRatio is the ratio of width to height assumed for candidates
+	// without height descriptor.
+	AspectRatio float64
+	// BaseWidth is the width in image pixels of the 1x candidate, which
+	// is needed to estimate density candidates. Zero means unknown.
+	BaseWidth float64
+}
+
+// ByteSample is the measured transfer size of an image.
+type ByteSample struct {
+	Width, Height int64 // in image pixels
+	Bytes         int64
+}
+
+// DefaultByteModel assumes photos in 3:2 landscape format, encoded at about
+// 1.6 bits per pixel as typical for JPEG at medium quality.
+var DefaultByteModel = ByteModel{
+	BytesPerPixel: 0.2,
+	AspectRatio:   1.5,
+}
+
+// EstimateBytes predicts the transfer size of src according to m. It
+// reports false if the dimensions of src are unknown: for density candidates
+// without m.BaseWidth, and for candidates without height descriptor without
+// m.AspectRatio.
+func (src ImageSource) EstimateBytes(m ByteModel) (int64, bool) {
+	w, h, ok := m.dimensions(src.Candidate())
+	if !ok {
+		return 0, false
+	}
+	return m.bytes(w * h), true
+}
+
+// EstimateBytes returns the predicted transfer size of each candidate of s
+// according to m, or -1 for the candidates whose dimensions are unknown. See
+// ImageSource.EstimateBytes.
+func (s SourceSet) EstimateBytes(m ByteModel) []int64 {
+	sizes := make([]int64, len(s))
+	for i, src := range s {
+		n, ok := src.EstimateBytes(m)
+		if !ok {
+			n = -1
+		}
+		sizes[i] = n
+	}
+	return sizes
+}
+
+// ExcessBytes returns how many bytes more src takes to transfer than an
+// image that exactly fills a slot of slotWidth CSS pixels at the device
+// pixel ratio dpr, with the same aspect ratio as src. It is negative if src
+// is too small, and so upscaled. It reports false if the size of src cannot
+// be estimated or slotWidth is not positive.
+func (m ByteModel) ExcessBytes(src ImageSource, slotWidth, dpr float64) (int64, bool) {
+	w, h, ok := m.dimensions(src.Candidate())
+	if !ok || slotWidth <= 0 || w <= 0 {
+		return 0, false
+	}
+	idealWidth := slotWidth * dpr
+	ideal := m.bytes(idealWidth * idealWidth * h / w)
+	return m.bytes(w*h) - ideal, true
+}
+
+// dimensions returns the width and height of c in image pixels.
+func (m ByteModel) dimensions(c Candidate) (w, h float64, ok bool) {
+	switch {
+	case c.HasWidth:
+		w = float64(c.Width)
+	case m.BaseWidth > 0:
+		w = m.BaseWidth * c.density()
+	default:
+		return 0, 0, false
+	}
+
+	switch {
+	case c.HasWidth && c.HasHeight:
+		h = float64(c.Height)
+	case m.AspectRatio > 0:
+		h = w / m.AspectRatio
+	default:
+		return 0, 0, false
+	}
+	return w, h, true
+}
+
+// bytes predicts the transfer size of an image of the given pixel count.
+func (m ByteModel) bytes(pixels float64) int64 {
+	return int64(math.Round(pixels * m.bytesPerPixel(pixels)))
+}
+
+// bytesPerPixel interpolates linearly between the samples closest in pixel
+// count, and uses the bytes per pixel of the closest sample outside their
+// range.
+func (m ByteModel) bytesPerPixel(pixels float64) float64 {
+	type point struct{ pixels, bpp float64 }
+	points := make([]point, 0, len(m.Samples))
+	for _, s := range m.Samples {
+		if p := float64(s.Width * s.Height); p > 0 {
+			points = append(points, point{p, float64(s.Bytes) / p})
+		}
+	}
+	if len(points) == 0 {
+		return m.BytesPerPixel
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].pixels < points[j].pixels })
+
+	i := sort.Search(len(points), func(i int) bool { return points[i].pixels >= pixels })
+	switch {
+	case i == 0:
+		return points[0].bpp
+	case i == len(points):
+		return points[len(points)-1].bpp
+	}
+	lo, hi := points[i-1], points[i]
+	return lo.bpp + (hi.bpp-lo.bpp)*(pixels-lo.pixels)/(hi.pixels-lo.pixels)
+}
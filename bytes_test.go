@@ -0,0 +1,76 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_EstimateBytes(t *testing.T) {
+	samples := ByteModel{
+		Samples: []ByteSample{
+			{Width: 200, Height: 100, Bytes: 10000}, // 0.5 bytes per pixel
+			{Width: 100, Height: 100, Bytes: 10000}, // 1 byte per pixel
+		},
+		AspectRatio: 2,
+	}
+
+	tests := []struct {
+		name   string
+		src    ImageSource
+		model  ByteModel
+		want   int64
+		wantOK bool
+	}{
+		{name: "Width and height", src: ImageSource{Width: i(400), Height: i(300)}, model: DefaultByteModel, want: 24000, wantOK: true},
+		{name: "Width only", src: ImageSource{Width: i(300)}, model: DefaultByteModel, want: 12000, wantOK: true},
+		{name: "Width without aspect ratio", src: ImageSource{Width: i(300)}, model: ByteModel{BytesPerPixel: 1}},
+		{name: "Density without base width", src: ImageSource{Density: fl(2)}, model: DefaultByteModel},
+		{name: "Density", src: ImageSource{Density: fl(2)}, model: ByteModel{BytesPerPixel: 1, AspectRatio: 1, BaseWidth: 100}, want: 40000, wantOK: true},
+		{name: "Default density", src: ImageSource{}, model: ByteModel{BytesPerPixel: 1, AspectRatio: 1, BaseWidth: 100}, want: 10000, wantOK: true},
+		{name: "Below samples", src: ImageSource{Width: i(50), Height: i(50)}, model: samples, want: 2500, wantOK: true},
+		{name: "Between samples", src: ImageSource{Width: i(150), Height: i(100)}, model: samples, want: 11250, wantOK: true},
+		{name: "Above samples", src: ImageSource{Width: i(400)}, model: samples, want: 40000, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.src.EstimateBytes(tt.model)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("%q. EstimateBytes() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	set := SourceSet{{Width: i(300)}, {Density: fl(2)}}
+	if got, want := set.EstimateBytes(DefaultByteModel), []int64{12000, -1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EstimateBytes() = %v, want %v", got, want)
+	}
+}
+
+func Test_ExcessBytes(t *testing.T) {
+	m := ByteModel{BytesPerPixel: 1, AspectRatio: 2}
+
+	tests := []struct {
+		name   string
+		src    ImageSource
+		slot   float64
+		dpr    float64
+		want   int64
+		wantOK bool
+	}{
+		{name: "Exact", src: ImageSource{Width: i(400)}, slot: 200, dpr: 2, want: 0, wantOK: true},
+		{name: "Too large", src: ImageSource{Width: i(400)}, slot: 200, dpr: 1, want: 60000, wantOK: true},
+		{name: "Too small", src: ImageSource{Width: i(200), Height: i(200)}, slot: 200, dpr: 2, want: -120000, wantOK: true},
+		{name: "No slot", src: ImageSource{Width: i(400)}, slot: 0, dpr: 1},
+		{name: "Unknown size", src: ImageSource{Density: fl(1)}, slot: 200, dpr: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.ExcessBytes(tt.src, tt.slot, tt.dpr)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("%q. ExcessBytes() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package srcset
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// CachedParser is a Parser that keeps the results of the most recently used
+// inputs in an LRU cache. A CachedParser is safe for concurrent use.
+type CachedParser struct {
+	parser     *Parser
+	maxEntries int
+	hits       uint64
+	misses     uint64
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	input string
+	set   SourceSet
+}
+
+// NewCachedParser returns a CachedParser configured with opts, that caches
+// up to maxEntries results.
+func NewCachedParser(maxEntries int, opts ...Option) *CachedParser {
+	return &CachedParser{
+		parser:     NewParser(opts...),
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+// Parse takes the value of a srcset attribute and parses it, or returns the
+// cached result for the same input. The result is shared between callers and
+// must not be modified; use Clone to obtain a modifiable copy. Warnings are
+// only reported when the input is actually parsed.
+func (p *CachedParser) Parse(input string) SourceSet {
+	p.mu.Lock()
+	if el, ok := p.entries[input]; ok {
+		p.lru.MoveToFront(el)
+		p.mu.Unlock()
+		atomic.AddUint64(&p.hits, 1)
+		return el.Value.(*cacheEntry).set
+	}
+	p.mu.Unlock()
+
+	atomic.AddUint64(&p.misses, 1)
+	set := p.parser.Parse(input)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if el, ok := p.entries[input]; ok {
+		// Another goroutine parsed the same input in the meantime.
+		p.lru.MoveToFront(el)
+		return el.Value.(*cacheEntry).set
+	}
+	if p.maxEntries <= 0 {
+		return set
+	}
+	p.entries[input] = p.lru.PushFront(&cacheEntry{input: input, set: set})
+	for p.lru.Len() > p.maxEntries {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.entries, oldest.Value.(*cacheEntry).input)
+	}
+
+	return set
+}
+
+// Hits returns the number of calls to Parse that were served from the cache.
+func (p *CachedParser) Hits() uint64 {
+	return atomic.LoadUint64(&p.hits)
+}
+
+// Misses returns the number of calls to Parse that parsed their input.
+func (p *CachedParser) Misses() uint64 {
+	return atomic.LoadUint64(&p.misses)
+}
+
+// Len returns the number of cached results.
+func (p *CachedParser) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lru.Len()
+}
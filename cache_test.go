@@ -0,0 +1,61 @@
+package srcset
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_CachedParser(t *testing.T) {
+	p := NewCachedParser(2)
+
+	inputs := []string{"a.png 1x", "b.png 2x", "a.png 1x", "c.png 3x", "b.png 2x", "a.png 1x"}
+	for _, input := range inputs {
+		if got, want := p.Parse(input), Parse(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	// b.png is evicted by c.png, and a.png by b.png.
+	if got := p.Hits(); got != 1 {
+		t.Errorf("Hits() = %d, want 1", got)
+	}
+	if got := p.Misses(); got != 5 {
+		t.Errorf("Misses() = %d, want 5", got)
+	}
+	if got := p.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+}
+
+func Test_CachedParser_shared(t *testing.T) {
+	p := NewCachedParser(10)
+	a, b := p.Parse("a.png 1x"), p.Parse("a.png 1x")
+	if &a[0] != &b[0] {
+		t.Errorf("Parse() did not return the cached SourceSet")
+	}
+}
+
+func Test_CachedParser_concurrent(t *testing.T) {
+	p := NewCachedParser(4)
+	inputs := []string{"a.png 1x", "b.png 2x", "c.png 3x", "d.png 4x", "e.png 5x"}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				input := inputs[j%len(inputs)]
+				if got := p.Parse(input); got.String() != input {
+					t.Errorf("Parse(%q) = %q", input, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := p.Hits() + p.Misses(); got != 800 {
+		t.Errorf("Hits() + Misses() = %d, want 800", got)
+	}
+}
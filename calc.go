@@ -0,0 +1,209 @@
+package srcset
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// sizeValue is a source size value that resolves to CSS pixels: a length,
+// or a calc() expression.
+type sizeValue interface {
+	px(ctx EvalContext) float64
+}
+
+// calcNode is a node of a calc() expression. Numbers are unitless values,
+// which may only be multiplied with or divide lengths.
+type calcNode struct {
+	op          byte // '+', '-', '*' or '/', or 0 for a leaf
+	left, right *calcNode
+	leaf        length // a length, or a number with an empty unit
+	isNumber    bool
+}
+
+func (n *calcNode) px(ctx EvalContext) float64 {
+	switch n.op {
+	case '+':
+		return n.left.px(ctx) + n.right.px(ctx)
+	case '-':
+		return n.left.px(ctx) - n.right.px(ctx)
+	case '*':
+		return n.left.px(ctx) * n.right.px(ctx)
+	case '/':
+		return n.left.px(ctx) / n.right.px(ctx)
+	}
+	if n.isNumber {
+		return n.leaf.value
+	}
+	return n.leaf.px(ctx)
+}
+
+// calcExpr is a calc() expression. Its value is clamped to zero, as source
+// sizes cannot be negative.
+type calcExpr struct {
+	root *calcNode
+}
+
+func (c calcExpr) px(ctx EvalContext) float64 {
+	if v := c.root.px(ctx); v > 0 {
+		return v
+	}
+	return 0
+}
+
+var errInvalidCalc = errors.New("invalid calc() expression")
+
+// isCalc reports whether input is a calc() function.
+func isCalc(input string) bool {
+	return len(input) > 5 && strings.EqualFold(input[:5], "calc(") && input[len(input)-1] == rightParens
+}
+
+// parseCalc parses a calc() expression whose result is a length. The
+// operands are lengths, numbers, parenthesized expressions and nested calc()
+// functions. As in CSS, + and - must be surrounded by whitespace.
+func parseCalc(input string) (calcExpr, error) {
+	toks, err := tokenizeCalc(input[5 : len(input)-1])
+	if err != nil {
+		return calcExpr{}, err
+	}
+
+	p := &calcParser{toks: toks}
+	root, err := p.sum()
+	if err != nil {
+		return calcExpr{}, err
+	}
+	if p.pos != len(p.toks) || root.isNumber {
+		return calcExpr{}, errInvalidCalc
+	}
+	return calcExpr{root: root}, nil
+}
+
+// tokenizeCalc splits the inside of a calc() function into operators,
+// parentheses and operands. "calc(" is turned into a plain parenthesis.
+func tokenizeCalc(input string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case isSpace(rune(c)):
+			i++
+		case c == leftParens || c == rightParens || c == '*' || c == '/':
+			toks = append(toks, input[i:i+1])
+			i++
+		case (c == '+' || c == '-') && (i+1 == len(input) || isSpace(rune(input[i+1]))):
+			if i == 0 || !isSpace(rune(input[i-1])) {
+				return nil, errInvalidCalc
+			}
+			toks = append(toks, input[i:i+1])
+			i++
+		case len(input)-i > 5 && strings.EqualFold(input[i:i+5], "calc("):
+			toks = append(toks, "(")
+			i += 5
+		default:
+			j := i + 1
+			for j < len(input) && !isSpace(rune(input[j])) && !strings.ContainsRune("()*/", rune(input[j])) &&
+				!((input[j] == '+' || input[j] == '-') && !isExponent(input, j)) {
+				j++
+			}
+			toks = append(toks, input[i:j])
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+// isExponent reports whether the sign at input[i] belongs to the exponent of
+// a number, such as in "1e-3px".
+func isExponent(input string, i int) bool {
+	return i > 1 && (input[i-1] == 'e' || input[i-1] == 'E') && input[i-2] >= '0' && input[i-2] <= '9'
+}
+
+type calcParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *calcParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *calcParser) sum() (*calcNode, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == "+" || op == "-"; op = p.peek() {
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		if left.isNumber != right.isNumber {
+			return nil, errInvalidCalc
+		}
+		left = &calcNode{op: op[0], left: left, right: right, isNumber: left.isNumber}
+	}
+	return left, nil
+}
+
+func (p *calcParser) product() (*calcNode, error) {
+	left, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == "*" || op == "/"; op = p.peek() {
+		p.pos++
+		right, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case op == "*" && !left.isNumber && !right.isNumber:
+			return nil, errInvalidCalc
+		case op == "/" && (!right.isNumber || right.px(EvalContext{}) == 0):
+			return nil, errInvalidCalc
+		}
+		left = &calcNode{op: op[0], left: left, right: right, isNumber: left.isNumber && right.isNumber}
+	}
+	return left, nil
+}
+
+func (p *calcParser) value() (*calcNode, error) {
+	tok := p.peek()
+	p.pos++
+
+	switch tok {
+	case "":
+		return nil, errInvalidCalc
+	case "(":
+		n, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errInvalidCalc
+		}
+		p.pos++
+		return n, nil
+	}
+
+	m := regexLength.FindStringSubmatch(strings.ToLower(tok))
+	if m == nil {
+		return nil, errInvalidCalc
+	}
+	if m[2] == "" {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		return &calcNode{leaf: length{value: v}, isNumber: true}, nil
+	}
+	l, err := parseLength(tok)
+	if err != nil {
+		return nil, err
+	}
+	return &calcNode{leaf: l}, nil
+}
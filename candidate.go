@@ -0,0 +1,90 @@
+package srcset
+
+// Candidate is an image candidate with value semantics. Unlike ImageSource,
+// it can be copied and compared with == without aliasing descriptor values.
+// Descriptor values are only meaningful if the corresponding Has field is
+// set. Custom and raw descriptors are not represented.
+type Candidate struct {
+	URL        string
+	Width      int64
+	Height     int64
+	Density    float64
+	HasWidth   bool
+	HasHeight  bool
+	HasDensity bool
+	Offset     int
+}
+
+// Candidates is a list of image candidates with value semantics.
+type Candidates []Candidate
+
+// ParseCandidates takes the value of a srcset attribute and parses it into
+// Candidates.
+func ParseCandidates(input string, opts ...Option) Candidates {
+	return Parse(input, opts...).Candidates()
+}
+
+// Candidate converts src to a Candidate.
+func (src ImageSource) Candidate() Candidate {
+	c := Candidate{URL: src.URL, Offset: src.Offset}
+	if src.Width != nil {
+		c.Width, c.HasWidth = *src.Width, true
+	}
+	if src.Height != nil {
+		c.Height, c.HasHeight = *src.Height, true
+	}
+	if src.Density != nil {
+		c.Density, c.HasDensity = *src.Density, true
+	}
+	return c
+}
+
+// ImageSource converts c to an ImageSource.
+func (c Candidate) ImageSource() ImageSource {
+	src := ImageSource{URL: c.URL, Offset: c.Offset}
+	if c.HasWidth {
+		w := c.Width
+		src.Width = &w
+	}
+	if c.HasHeight {
+		h := c.Height
+		src.Height = &h
+	}
+	if c.HasDensity {
+		d := c.Density
+		src.Density = &d
+	}
+	return src
+}
+
+// Candidates converts s to Candidates.
+func (s SourceSet) Candidates() Candidates {
+	if s == nil {
+		return nil
+	}
+	candidates := make(Candidates, len(s))
+	for i, src := range s {
+		candidates[i] = src.Candidate()
+	}
+	return candidates
+}
+
+// SourceSet converts c to a SourceSet.
+func (c Candidates) SourceSet() SourceSet {
+	if c == nil {
+		return nil
+	}
+	set := make(SourceSet, len(c))
+	for i, candidate := range c {
+		set[i] = candidate.ImageSource()
+	}
+	return set
+}
+
+// density returns the density descriptor of c, or 1 when it has none.
+func (c Candidate) density() float64 {
+	if c.HasDensity {
+		return c.Density
+	}
+	return 1
+}
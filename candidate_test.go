@@ -0,0 +1,59 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ParseCandidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Candidates
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			want:  Candidates{},
+		},
+		{
+			name:  "Densities",
+			input: "a.png, b.png 2x",
+			want: Candidates{
+				{URL: "a.png", Offset: 0},
+				{URL: "b.png", Density: 2, HasDensity: true, Offset: 7},
+			},
+		},
+		{
+			name:  "Width and height",
+			input: "a.png 320w 200h",
+			want: Candidates{
+				{URL: "a.png", Width: 320, HasWidth: true, Height: 200, HasHeight: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseCandidates(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ParseCandidates() = %v, want %v", tt.name, got, tt.want)
+			}
+			if set := got.SourceSet(); !reflect.DeepEqual(set, Parse(tt.input)) {
+				t.Errorf("%q. SourceSet() = %v, want %v", tt.name, set, Parse(tt.input))
+			}
+		})
+	}
+}
+
+func Test_Candidate_Equal(t *testing.T) {
+	a := Candidate{URL: "a.png", Width: 320, HasWidth: true, Offset: 3}
+	b := Candidate{URL: "a.png", Width: 320, HasWidth: true, Density: 2, Offset: 9}
+	if !a.Equal(b) {
+		t.Errorf("Equal() = false, want true")
+	}
+	b.HasDensity = true
+	if a.Equal(b) {
+		t.Errorf("Equal() = true, want false")
+	}
+}
@@ -0,0 +1,221 @@
+package srcset
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// HostForm selects how Canonicalize writes internationalized hostnames.
+type HostForm int
+
+const (
+	// HostAsIs keeps hostnames as they are, apart from lowercasing.
+	HostAsIs HostForm = iota
+	// HostASCII converts hostnames to their ASCII form, such as
+	// xn--bcher-kva.de, so that both forms of a hostname compare equal.
+	HostASCII
+	// HostUnicode converts hostnames to their Unicode form, such as
+	// bücher.de, for display.
+	HostUnicode
+)
+
+// CanonicalOption configures Canonicalize.
+type CanonicalOption func(*canonicalConfig)
+
+type canonicalConfig struct {
+	hostForm HostForm
+}
+
+// WithHostForm makes Canonicalize write internationalized hostnames in the
+// given form. Hostnames that are not valid IDNA are left as they are.
+func WithHostForm(form HostForm) CanonicalOption {
+	return func(c *canonicalConfig) {
+		c.hostForm = form
+	}
+}
+
+// Canonicalize returns a copy of s in which every candidate URL is brought
+// into a canonical form, so that equivalent URLs compare equal: the scheme
+// and host are lowercased, default ports are removed, percent-encodings are
+// normalized, and dot-segments are removed from absolute paths. URLs that
+// cannot be parsed are left unchanged.
+func (s SourceSet) Canonicalize(opts ...CanonicalOption) SourceSet {
+	var cfg canonicalConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	canonical := s.Clone()
+	for i := range canonical {
+		canonical[i].URL = canonicalURL(canonical[i].URL, cfg.hostForm)
+	}
+	return canonical
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+func canonicalURL(raw string, form HostForm) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Opaque != "" {
+		return u.String()
+	}
+	var unicodeHost string
+	if u.Host != "" {
+		host, port := strings.ToLower(u.Hostname()), u.Port()
+		if form != HostAsIs {
+			// The host is written in ASCII, as url.URL escapes other
+			// characters; the Unicode form is substituted afterwards.
+			host = toASCIIHost(host)
+			if form == HostUnicode {
+				unicodeHost = toUnicodeHost(host)
+			}
+		}
+		if port == defaultPorts[u.Scheme] {
+			port = ""
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if port != "" {
+			host += ":" + port
+		}
+		u.Host = host
+		if unicodeHost != "" && port != "" {
+			unicodeHost += ":" + port
+		}
+	}
+
+	path := normalizePercent(u.EscapedPath())
+	if u.Host != "" || strings.HasPrefix(path, "/") {
+		path = removeDotSegments(path)
+	}
+	if u.Host != "" && path == "" && u.Scheme != "" {
+		path = "/"
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		u.Path, u.RawPath = unescaped, path
+	}
+
+	u.RawQuery = normalizePercent(u.RawQuery)
+	if u.Fragment != "" {
+		fragment := normalizePercent(u.EscapedFragment())
+		if unescaped, err := url.PathUnescape(fragment); err == nil {
+			u.Fragment, u.RawFragment = unescaped, fragment
+		}
+	}
+
+	s := u.String()
+	if unicodeHost != "" {
+		prefix := u.Scheme + "://"
+		if u.User != nil {
+			prefix += u.User.String() + "@"
+		}
+		if strings.HasPrefix(s, prefix+u.Host) {
+			s = prefix + unicodeHost + s[len(prefix)+len(u.Host):]
+		}
+	}
+	return s
+}
+
+// toASCIIHost returns the ASCII form of host, or host if it is not a valid
+// internationalized hostname.
+func toASCIIHost(host string) string {
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		return ascii
+	}
+	return host
+}
+
+// toUnicodeHost returns the Unicode form of host, or host if it is not a
+// valid internationalized hostname.
+func toUnicodeHost(host string) string {
+	if unicode, err := idna.Display.ToUnicode(host); err == nil {
+		return unicode
+	}
+	return host
+}
+
+// normalizePercent uppercases the hex digits of percent-encodings in s, and
+// decodes percent-encoded unreserved characters.
+func normalizePercent(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+// removeDotSegments implements the algorithm of RFC 3986, section 5.2.4.
+func removeDotSegments(path string) string {
+	var out []string
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		switch seg {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, seg)
+		}
+	}
+	return strings.Join(out, "/")
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
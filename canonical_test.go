@@ -0,0 +1,76 @@
+package srcset
+
+import "testing"
+
+func Test_canonicalURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "a.png", want: "a.png"},
+		{url: "../img/./a.png", want: "../img/./a.png"},
+		{url: "HTTPS://Example.COM:443/a.png", want: "https://example.com/a.png"},
+		{url: "http://example.com:8080/a.png", want: "http://example.com:8080/a.png"},
+		{url: "http://example.com", want: "http://example.com/"},
+		{url: "http://example.com/a/./b/../c.png", want: "http://example.com/a/c.png"},
+		{url: "/a/../../b.png", want: "/b.png"},
+		{url: "http://example.com/%7euser/%c3%a9.png?q=%2f%41", want: "http://example.com/~user/%C3%A9.png?q=%2FA"},
+		{url: "//CDN.example.com/a.png", want: "//cdn.example.com/a.png"},
+		{url: "data:,a", want: "data:,a"},
+		{url: "DATA:,a", want: "data:,a"},
+		{url: "http://example.com/a.png#%7Efrag", want: "http://example.com/a.png#~frag"},
+		{url: "http://[::1]:80/a.png", want: "http://[::1]/a.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := canonicalURL(tt.url, HostAsIs); got != tt.want {
+				t.Errorf("canonicalURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Canonicalize(t *testing.T) {
+	set := Parse("HTTP://Example.com:80/a.png 1x, b.png 2x")
+	got := set.Canonicalize()
+	if want := "http://example.com/a.png 1x, b.png 2x"; got.String() != want {
+		t.Errorf("Canonicalize() = %q, want %q", got, want)
+	}
+	if set[0].URL != "HTTP://Example.com:80/a.png" {
+		t.Errorf("Canonicalize() modified the original set")
+	}
+}
+
+func Test_Canonicalize_hostForm(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		form HostForm
+		want string
+	}{
+		{name: "As is", url: "https://Bücher.de/a.png", form: HostAsIs, want: "https://b%C3%BCcher.de/a.png"},
+		{name: "ASCII", url: "https://Bücher.de/a.png", form: HostASCII, want: "https://xn--bcher-kva.de/a.png"},
+		{name: "ASCII from ASCII", url: "https://XN--BCHER-KVA.de/a.png", form: HostASCII, want: "https://xn--bcher-kva.de/a.png"},
+		{name: "Unicode", url: "https://user@xn--bcher-kva.de:8080/a.png", form: HostUnicode, want: "https://user@bücher.de:8080/a.png"},
+		{name: "Unicode default port", url: "https://XN--BCHER-KVA.DE:443/a.png", form: HostUnicode, want: "https://bücher.de/a.png"},
+		{name: "Protocol-relative", url: "//bücher.de/a.png", form: HostASCII, want: "//xn--bcher-kva.de/a.png"},
+		{name: "Invalid", url: "https://a_b.example/a.png", form: HostASCII, want: "https://a_b.example/a.png"},
+		{name: "Relative", url: "a.png", form: HostUnicode, want: "a.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.url).Canonicalize(WithHostForm(tt.form))
+			if got[0].URL != tt.want {
+				t.Errorf("%q. Canonicalize() = %q, want %q", tt.name, got[0].URL, tt.want)
+			}
+		})
+	}
+
+	a := Parse("https://bücher.de/a.png 1x").Canonicalize(WithHostForm(HostASCII))
+	b := Parse("https://xn--bcher-kva.de/a.png 1x").Canonicalize(WithHostForm(HostASCII))
+	if !a.Equal(b) {
+		t.Errorf("Canonicalize() = %v and %v, want equal sets", a, b)
+	}
+}
@@ -0,0 +1,45 @@
+// Package cdn builds srcset attributes for images served by image CDNs,
+// which resize a master image according to parameters in the URL.
+package cdn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lukasbob/srcset"
+)
+
+// Builder returns the URL of a master image resized to a width in pixels.
+type Builder interface {
+	URL(master string, width int64) (string, error)
+}
+
+// BuilderFunc adapts a function to a Builder.
+type BuilderFunc func(master string, width int64) (string, error)
+
+// URL calls f(master, width).
+func (f BuilderFunc) URL(master string, width int64) (string, error) {
+	return f(master, width)
+}
+
+// Build returns a set with a width candidate for each of widths, whose URLs
+// are built by b.
+func Build(b Builder, master string, widths []int64) (srcset.SourceSet, error) {
+	if len(widths) == 0 {
+		return nil, errors.New("cdn: no widths")
+	}
+
+	set := make(srcset.SourceSet, len(widths))
+	for i, w := range widths {
+		if w <= 0 {
+			return nil, fmt.Errorf("cdn: width %d is not greater than zero", w)
+		}
+		url, err := b.URL(master, w)
+		if err != nil {
+			return nil, err
+		}
+		width := w
+		set[i] = srcset.ImageSource{URL: url, Width: &width}
+	}
+	return set, nil
+}
@@ -0,0 +1,102 @@
+package cdn
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func Test_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder Builder
+		master  string
+		widths  []int64
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Imgix",
+			builder: Imgix{Params: url.Values{"auto": {"format"}}},
+			master:  "https://example.imgix.net/photo.jpg?fit=max",
+			widths:  []int64{320, 640},
+			want: "https://example.imgix.net/photo.jpg?auto=format&fit=max&w=320 320w, " +
+				"https://example.imgix.net/photo.jpg?auto=format&fit=max&w=640 640w",
+		},
+		{
+			name:    "Cloudinary upload",
+			builder: Cloudinary{CloudName: "demo", Transformations: "f_auto,q_auto"},
+			master:  "samples/photo.jpg",
+			widths:  []int64{320},
+			want:    "https://res.cloudinary.com/demo/image/upload/w_320,f_auto,q_auto/samples/photo.jpg 320w",
+		},
+		{
+			name:    "Cloudinary fetch",
+			builder: Cloudinary{CloudName: "demo"},
+			master:  "https://example.com/photo.jpg?v=2",
+			widths:  []int64{320},
+			want:    "https://res.cloudinary.com/demo/image/fetch/w_320/https%3A%2F%2Fexample.com%2Fphoto.jpg%3Fv%3D2 320w",
+		},
+		{
+			name:    "Cloudinary without cloud name",
+			builder: Cloudinary{},
+			master:  "photo.jpg",
+			widths:  []int64{320},
+			wantErr: true,
+		},
+		{
+			name:    "Imgproxy unsigned",
+			builder: Imgproxy{BaseURL: "https://imgproxy.example.com/", Options: "q:80"},
+			master:  "s3://bucket/photo.jpg",
+			widths:  []int64{320},
+			want:    "https://imgproxy.example.com/insecure/rs:fit:320:0/q:80/plain/s3://bucket/photo.jpg 320w",
+		},
+		{
+			name:    "Imgproxy signed",
+			builder: Imgproxy{BaseURL: "https://imgproxy.example.com", Key: []byte("key"), Salt: []byte("salt")},
+			master:  "s3://bucket/photo.jpg",
+			widths:  []int64{320},
+			want:    "https://imgproxy.example.com/a9PrlCxK0CJp5nVmLS5KRoCpJRU13ZHWvQ36xgkZB_A/rs:fit:320:0/plain/s3://bucket/photo.jpg 320w",
+		},
+		{
+			name:    "Imgproxy without base URL",
+			builder: Imgproxy{},
+			master:  "photo.jpg",
+			widths:  []int64{320},
+			wantErr: true,
+		},
+		{
+			name:    "No widths",
+			builder: Imgix{},
+			master:  "https://example.imgix.net/photo.jpg",
+			wantErr: true,
+		},
+		{
+			name:    "Zero width",
+			builder: Imgix{},
+			master:  "https://example.imgix.net/photo.jpg",
+			widths:  []int64{0},
+			wantErr: true,
+		},
+		{
+			name: "Builder func error",
+			builder: BuilderFunc(func(string, int64) (string, error) {
+				return "", errors.New("unavailable")
+			}),
+			widths:  []int64{320},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Build(tt.builder, tt.master, tt.widths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. Build() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if s := got.String(); s != tt.want {
+				t.Errorf("%q. Build() = %q, want %q", tt.name, s, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package cdn
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Cloudinary builds URLs for Cloudinary. The master is either the public ID
+// of an uploaded image, or the absolute URL of a remote image, which is
+// fetched by Cloudinary.
+type Cloudinary struct {
+	CloudName string
+	// Transformations are added to every URL, such as "f_auto,q_auto".
+	Transformations string
+}
+
+// URL implements Builder by adding a "w_" transformation.
+func (b Cloudinary) URL(master string, width int64) (string, error) {
+	if b.CloudName == "" {
+		return "", errors.New("cdn: missing Cloudinary cloud name")
+	}
+
+	deliveryType := "upload"
+	if strings.HasPrefix(master, "http://") || strings.HasPrefix(master, "https://") {
+		deliveryType = "fetch"
+		if strings.ContainsAny(master, "?#") {
+			master = url.QueryEscape(master)
+		}
+	}
+
+	transformations := "w_" + strconv.FormatInt(width, 10)
+	if b.Transformations != "" {
+		transformations += "," + b.Transformations
+	}
+
+	return "https://res.cloudinary.com/" + b.CloudName + "/image/" + deliveryType + "/" + transformations + "/" + strings.TrimPrefix(master, "/"), nil
+}
@@ -0,0 +1,30 @@
+package cdn
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Imgix builds URLs for imgix. The master is the URL of the image on an
+// imgix source, such as "https://example.imgix.net/photo.jpg".
+type Imgix struct {
+	// Params are added to every URL, such as "auto=format".
+	Params url.Values
+}
+
+// URL implements Builder by setting the "w" parameter.
+func (b Imgix) URL(master string, width int64) (string, error) {
+	u, err := url.Parse(master)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	for key, values := range b.Params {
+		q[key] = values
+	}
+	q.Set("w", strconv.FormatInt(width, 10))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
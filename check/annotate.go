@@ -0,0 +1,49 @@
+package check
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/lukasbob/srcset"
+)
+
+// TransferSizes maps the index of a candidate in a set to its transfer size
+// in bytes. The sizes are kept apart from the candidates, as a custom
+// descriptor would make browsers drop them when the set is serialized.
+type TransferSizes map[int]int64
+
+// Annotate checks every candidate of set with a Checker using client, and
+// returns the transfer size of each candidate whose Content-Length is known,
+// along with the results.
+func Annotate(ctx context.Context, set srcset.SourceSet, client *http.Client) (TransferSizes, []Result) {
+	return (&Checker{Client: client}).Annotate(ctx, set)
+}
+
+// Annotate checks every candidate of set, and returns the transfer size of
+// each candidate whose Content-Length is known, along with the results.
+func (c *Checker) Annotate(ctx context.Context, set srcset.SourceSet) (TransferSizes, []Result) {
+	results := c.Check(ctx, set)
+	sizes := TransferSizes{}
+	for i, res := range results {
+		if res.OK() && res.ContentLength >= 0 {
+			sizes[i] = res.ContentLength
+		}
+	}
+	return sizes, results
+}
+
+// Largest returns the candidate of set with the largest transfer size, and
+// its size. It reports false if no candidate has a size.
+func (s TransferSizes) Largest(set srcset.SourceSet) (srcset.ImageSource, int64, bool) {
+	var (
+		largest srcset.ImageSource
+		max     int64
+		found   bool
+	)
+	for i, src := range set {
+		if n, ok := s[i]; ok && (!found || n > max) {
+			largest, max, found = src, n, true
+		}
+	}
+	return largest, max, found
+}
@@ -0,0 +1,63 @@
+package check
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lukasbob/srcset"
+)
+
+func Test_Annotate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/small.png":
+			w.Header().Set("Content-Length", "1234")
+		case "/large.png":
+			w.Header().Set("Content-Length", "56789")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	set := srcset.Parse(srv.URL + "/small.png 1x, " + srv.URL + "/large.png 2x, " + srv.URL + "/missing.png 3x")
+	want := set.String()
+	sizes, results := Annotate(context.Background(), set, srv.Client())
+	if len(results) != len(set) {
+		t.Fatalf("Annotate() returned %d results, want %d", len(results), len(set))
+	}
+
+	tests := []struct {
+		name   string
+		want   int64
+		wantOK bool
+	}{
+		{name: "Small", want: 1234, wantOK: true},
+		{name: "Large", want: 56789, wantOK: true},
+		{name: "Missing", want: 0, wantOK: false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, ok := sizes[i]
+			if n != tt.want || ok != tt.wantOK {
+				t.Errorf("%q. sizes[%d] = %d, %v, want %d, %v", tt.name, i, n, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// The sizes do not end up in the serialized set.
+	if got := set.String(); got != want {
+		t.Errorf("Annotate() modified the set: %s, want %s", got, want)
+	}
+
+	src, n, ok := sizes.Largest(set)
+	if !ok || n != 56789 || src.URL != srv.URL+"/large.png" {
+		t.Errorf("Largest() = %s, %d, %v, want %s, 56789, true", src.URL, n, ok, srv.URL+"/large.png")
+	}
+	if _, _, ok := (TransferSizes{}).Largest(set); ok {
+		t.Errorf("Largest() without sizes reports a candidate")
+	}
+}
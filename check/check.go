@@ -0,0 +1,170 @@
+// Package check verifies over the network that the candidates of a srcset
+// attribute exist, and optionally that their dimensions match their width
+// descriptors. Annotate records their transfer sizes on the candidates.
+//
+// Dimensions are decoded with image.DecodeConfig. Decoders for GIF, JPEG and
+// PNG are registered by this package; others, such as WebP, must be
+// registered by the program.
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register decoder for dimension checks
+	_ "image/jpeg" // register decoder for dimension checks
+	_ "image/png"  // register decoder for dimension checks
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/lukasbob/srcset"
+)
+
+// DefaultConcurrency is the number of concurrent requests made by a Checker
+// whose Concurrency is zero.
+const DefaultConcurrency = 4
+
+// maxDecodeBytes limits how much of an image is read to decode its
+// dimensions.
+const maxDecodeBytes = 1 << 20
+
+// ErrNotAbsolute is reported for candidates whose URL is not an absolute
+// HTTP or HTTPS URL, which cannot be requested.
+var ErrNotAbsolute = errors.New("check: not an absolute HTTP URL")
+
+// Result is the outcome of checking a single candidate.
+type Result struct {
+	Source        srcset.ImageSource
+	StatusCode    int
+	ContentType   string
+	ContentLength int64 // -1 if unknown
+	Err           error
+
+	// Width and Height are the decoded dimensions of the image in pixels,
+	// or zero if they were not verified.
+	Width, Height int
+	// Mismatch reports whether Width differs from the width descriptor of
+	// the candidate by more than the tolerance of the Checker.
+	Mismatch bool
+}
+
+// OK reports whether the candidate was retrieved successfully.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Checker checks candidates with HEAD requests, falling back to GET requests
+// for servers that do not support HEAD.
+type Checker struct {
+	Client      *http.Client // http.DefaultClient if nil
+	Concurrency int          // DefaultConcurrency if zero
+
+	// VerifyDimensions makes the Checker fetch the candidates with GET
+	// requests and decode their dimensions. Only as much of each image is
+	// read as needed.
+	VerifyDimensions bool
+	// WidthTolerance is the relative difference between the decoded width
+	// and the width descriptor that is tolerated, such as 0.01 for 1%.
+	WidthTolerance float64
+}
+
+// Check checks every candidate of set with a Checker using client, and
+// returns the results in the order of the candidates.
+func Check(ctx context.Context, set srcset.SourceSet, client *http.Client) []Result {
+	return (&Checker{Client: client}).Check(ctx, set)
+}
+
+// Check checks every candidate of set and returns the results in the order
+// of the candidates. Data URLs are not requested; their results only hold
+// the content type.
+func (c *Checker) Check(ctx context.Context, set srcset.SourceSet) []Result {
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var (
+		results = make([]Result, len(set))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i, src := range set {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, src srcset.ImageSource) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = c.check(ctx, src)
+		}(i, src)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (c *Checker) check(ctx context.Context, src srcset.ImageSource) Result {
+	res := Result{Source: src, ContentLength: -1}
+
+	lower := strings.ToLower(src.URL)
+	switch {
+	case strings.HasPrefix(lower, "data:"):
+		res.ContentType = src.GuessType()
+		return res
+	case !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://"):
+		res.Err = ErrNotAbsolute
+		return res
+	}
+
+	method := http.MethodHead
+	if c.VerifyDimensions {
+		method = http.MethodGet
+	}
+
+	resp, err := c.do(ctx, method, src.URL)
+	if err == nil && method == http.MethodHead && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = c.do(ctx, http.MethodGet, src.URL)
+	}
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+
+	res.StatusCode = resp.StatusCode
+	res.ContentType = resp.Header.Get("Content-Type")
+	res.ContentLength = resp.ContentLength
+
+	if c.VerifyDimensions && res.OK() {
+		cfg, _, err := image.DecodeConfig(io.LimitReader(resp.Body, maxDecodeBytes))
+		if err != nil {
+			res.Err = fmt.Errorf("check: decoding %s: %w", src.URL, err)
+			return res
+		}
+		res.Width, res.Height = cfg.Width, cfg.Height
+		if src.Width != nil {
+			diff := math.Abs(float64(res.Width) - float64(*src.Width))
+			res.Mismatch = diff > c.WidthTolerance*float64(*src.Width)
+		}
+	}
+	return res
+}
+
+func (c *Checker) do(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,136 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lukasbob/srcset"
+)
+
+func Test_Check(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Length", "1234")
+		case "/get-only.webp":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.Header().Set("Content-Type", "image/webp")
+			w.Write([]byte("RIFF"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	set := srcset.Parse(srv.URL + "/a.png 1x, " + srv.URL + "/get-only.webp 2x, " + srv.URL + "/missing.png 3x, rel.png 4x, data:image/gif;base64,R0lG 5x")
+	tests := []struct {
+		name          string
+		statusCode    int
+		contentType   string
+		contentLength int64
+		ok            bool
+		err           error
+	}{
+		{name: "HEAD", statusCode: 200, contentType: "image/png", contentLength: 1234, ok: true},
+		{name: "GET fallback", statusCode: 200, contentType: "image/webp", contentLength: 4, ok: true},
+		{name: "Not found", statusCode: 404, contentType: "text/plain; charset=utf-8", contentLength: 19},
+		{name: "Relative URL", contentLength: -1, err: ErrNotAbsolute},
+		{name: "Data URL", contentType: "image/gif", contentLength: -1},
+	}
+
+	results := (&Checker{Client: srv.Client(), Concurrency: 2}).Check(context.Background(), set)
+	if len(results) != len(tests) {
+		t.Fatalf("Check() returned %d results, want %d", len(results), len(tests))
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := results[i]
+			if got.Source.URL != set[i].URL {
+				t.Errorf("%q. Source = %q, want %q", tt.name, got.Source.URL, set[i].URL)
+			}
+			if got.StatusCode != tt.statusCode {
+				t.Errorf("%q. StatusCode = %d, want %d", tt.name, got.StatusCode, tt.statusCode)
+			}
+			if got.ContentType != tt.contentType {
+				t.Errorf("%q. ContentType = %q, want %q", tt.name, got.ContentType, tt.contentType)
+			}
+			if got.ContentLength != tt.contentLength {
+				t.Errorf("%q. ContentLength = %d, want %d", tt.name, got.ContentLength, tt.contentLength)
+			}
+			if got.OK() != tt.ok {
+				t.Errorf("%q. OK() = %v, want %v", tt.name, got.OK(), tt.ok)
+			}
+			if got.Err != tt.err {
+				t.Errorf("%q. Err = %v, want %v", tt.name, got.Err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_Check_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := Check(ctx, srcset.Parse("http://127.0.0.1:1/a.png"), nil)
+	if len(results) != 1 || results[0].Err == nil {
+		t.Errorf("Check() = %v, want an error", results)
+	}
+}
+
+func Test_Check_dimensions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var width int
+		fmt.Sscanf(r.URL.Path, "/%d.png", &width)
+		if width == 0 {
+			w.Write([]byte("not an image"))
+			return
+		}
+		png.Encode(w, image.NewGray(image.Rect(0, 0, width, 10)))
+	}))
+	defer srv.Close()
+
+	set := srcset.Parse(srv.URL + "/100.png 100w, " + srv.URL + "/99.png 100w, " + srv.URL + "/90.png 100w, " + srv.URL + "/50.png 2x, " + srv.URL + "/a.txt 200w")
+	tests := []struct {
+		name     string
+		width    int
+		mismatch bool
+		wantErr  bool
+	}{
+		{name: "Exact", width: 100},
+		{name: "Within tolerance", width: 99},
+		{name: "Mismatch", width: 90, mismatch: true},
+		{name: "Density", width: 50},
+		{name: "Not an image", wantErr: true},
+	}
+
+	checker := &Checker{Client: srv.Client(), VerifyDimensions: true, WidthTolerance: 0.01}
+	results := checker.Check(context.Background(), set)
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := results[i]
+			if (got.Err != nil) != tt.wantErr {
+				t.Fatalf("%q. Err = %v, wantErr %v", tt.name, got.Err, tt.wantErr)
+			}
+			if got.Width != tt.width {
+				t.Errorf("%q. Width = %d, want %d", tt.name, got.Width, tt.width)
+			}
+			if got.Width != 0 && got.Height != 10 {
+				t.Errorf("%q. Height = %d, want %d", tt.name, got.Height, 10)
+			}
+			if got.Mismatch != tt.mismatch {
+				t.Errorf("%q. Mismatch = %v, want %v", tt.name, got.Mismatch, tt.mismatch)
+			}
+		})
+	}
+}
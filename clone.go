@@ -0,0 +1,41 @@
+package srcset
+
+// Clone returns a deep copy of src that shares no descriptor values with it.
+func (src ImageSource) Clone() ImageSource {
+	if src.Width != nil {
+		w := *src.Width
+		src.Width = &w
+	}
+	if src.Height != nil {
+		h := *src.Height
+		src.Height = &h
+	}
+	if src.Density != nil {
+		d := *src.Density
+		src.Density = &d
+	}
+	if src.Extensions != nil {
+		extensions := make(map[string]string, len(src.Extensions))
+		for key, value := range src.Extensions {
+			extensions[key] = value
+		}
+		src.Extensions = extensions
+	}
+	if src.RawDescriptors != nil {
+		src.RawDescriptors = append([]string(nil), src.RawDescriptors...)
+	}
+	return src
+}
+
+// Clone returns a deep copy of s, so that the copy can be modified without
+// affecting s.
+func (s SourceSet) Clone() SourceSet {
+	if s == nil {
+		return nil
+	}
+	clone := make(SourceSet, len(s))
+	for i, src := range s {
+		clone[i] = src.Clone()
+	}
+	return clone
+}
@@ -0,0 +1,34 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Clone(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+	}{
+		{name: "Nil", set: nil},
+		{name: "Empty", set: SourceSet{}},
+		{name: "Densities", set: Parse("a.png 1x, b.png 2x")},
+		{name: "Width and height", set: Parse("a.png 320w 200h")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.set.Clone()
+			if !reflect.DeepEqual(got, tt.set) {
+				t.Fatalf("%q. Clone() = %v, want %v", tt.name, got, tt.set)
+			}
+			for i := range got {
+				if got[i].Width != nil && got[i].Width == tt.set[i].Width ||
+					got[i].Height != nil && got[i].Height == tt.set[i].Height ||
+					got[i].Density != nil && got[i].Density == tt.set[i].Density {
+					t.Errorf("%q. Clone() shares descriptor values with the original", tt.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lukasbob/srcset"
+	"github.com/lukasbob/srcset/check"
+)
+
+func runCheck(e env, args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	concurrency := fs.Int("concurrency", check.DefaultConcurrency, "`number` of concurrent requests")
+	base := fs.String("base", "", "`URL` to resolve relative candidate URLs against")
+	dimensions := fs.Bool("dimensions", false, "download the images and verify their widths")
+	tolerance := fs.Float64("tolerance", 0, "tolerated relative width `difference`, such as 0.01 for 1%")
+	timeout := fs.Duration("timeout", 30*time.Second, "`timeout` of each request")
+	attrs := fs.String("attrs", defaultAttrs, "comma-separated `names` of the attributes to check")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: srcset check [flags] [file.html ...]")
+		fmt.Fprintln(e.stderr, "Requests every candidate and exits with 1 if any is broken. Reads stdin when")
+		fmt.Fprintln(e.stderr, "no file or - is given. Data URLs are not checked.")
+		fs.PrintDefaults()
+	}
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	var baseURL *url.URL
+	if *base != "" {
+		if baseURL, err = url.Parse(*base); err != nil || !baseURL.IsAbs() {
+			fmt.Fprintln(e.stderr, "srcset check: -base must be an absolute URL")
+			return exitUsage
+		}
+	}
+
+	found, err := extractFiles(e, files, splitNames(*attrs))
+	if err != nil {
+		fmt.Fprintf(e.stderr, "srcset check: %v\n", err)
+		return exitFailure
+	}
+
+	// Every distinct candidate is checked once, however often it occurs.
+	var (
+		set   srcset.SourceSet
+		index = map[string]int{}
+		refs  [][]int // for each attribute, the indices of its candidates in set
+	)
+	for _, x := range found {
+		var ref []int
+		for _, src := range x.set {
+			if strings.HasPrefix(strings.ToLower(src.URL), "data:") {
+				continue
+			}
+			if baseURL != nil {
+				src.URL = rebaseURL(src.URL, baseURL, "")
+			}
+			key := src.String()
+			i, ok := index[key]
+			if !ok {
+				i = len(set)
+				index[key] = i
+				set = append(set, src)
+			}
+			ref = append(ref, i)
+		}
+		refs = append(refs, ref)
+	}
+
+	checker := &check.Checker{
+		Client:           &http.Client{Timeout: *timeout},
+		Concurrency:      *concurrency,
+		VerifyDimensions: *dimensions,
+		WidthTolerance:   *tolerance,
+	}
+	results := checker.Check(context.Background(), set)
+
+	problems := 0
+	for i, x := range found {
+		for _, j := range refs[i] {
+			if msg := problem(results[j]); msg != "" {
+				problems++
+				fmt.Fprintf(e.stdout, "%s:%d:%d: %s %s: %s: %s\n", x.File, x.Line, x.Column, x.Selector, x.Attribute, results[j].Source.URL, msg)
+			}
+		}
+	}
+	fmt.Fprintf(e.stdout, "%d candidates checked, %d problems\n", len(set), problems)
+
+	if problems > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// problem describes what is wrong with the result of a check, if anything.
+func problem(res check.Result) string {
+	switch {
+	case res.Err != nil:
+		return res.Err.Error()
+	case !res.OK():
+		return fmt.Sprintf("status %d %s", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+
+	if typ, _, err := mime.ParseMediaType(res.ContentType); err != nil || !strings.HasPrefix(typ, "image/") {
+		return fmt.Sprintf("unexpected content type %q", res.ContentType)
+	}
+	if res.Mismatch {
+		return fmt.Sprintf("width %d does not match %dw", res.Width, *res.Source.Width)
+	}
+	return ""
+}
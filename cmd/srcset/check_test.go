@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_check(t *testing.T) {
+	encode := func(width int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, 1))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/a.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(encode(320))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		case "/small.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(encode(300))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	page := `<img id=ok srcset="/a.png 320w, data:image/png;base64,AAAA 640w">
+<img id=broken srcset="/a.png 320w, /missing.png 640w, /page.html 960w">
+<img id=small srcset="/small.png 320w">`
+
+	tests := []struct {
+		name         string
+		args         []string
+		want         []string
+		wantCode     int
+		wantRequests int32
+	}{
+		{
+			name: "Links",
+			args: []string{"-", "-base", srv.URL, "-concurrency", "2"},
+			want: []string{
+				"-:2:1: img#broken srcset: " + srv.URL + "/missing.png: status 404 Not Found",
+				"-:2:1: img#broken srcset: " + srv.URL + "/page.html: unexpected content type \"text/html; charset=utf-8\"",
+				"4 candidates checked, 2 problems",
+			},
+			wantCode:     exitFailure,
+			wantRequests: 4,
+		},
+		{
+			name: "Dimensions",
+			args: []string{"-base", srv.URL, "-dimensions", "-tolerance", "0.01"},
+			want: []string{
+				"-:2:1: img#broken srcset: " + srv.URL + "/missing.png: status 404 Not Found",
+				"-:2:1: img#broken srcset: " + srv.URL + "/page.html: check: decoding " + srv.URL + "/page.html: image: unknown format",
+				"-:3:1: img#small srcset: " + srv.URL + "/small.png: width 300 does not match 320w",
+				"4 candidates checked, 3 problems",
+			},
+			wantCode:     exitFailure,
+			wantRequests: 4,
+		},
+		{
+			name:     "Relative without base",
+			args:     []string{},
+			want:     []string{"-:1:1: img#ok srcset: /a.png: check: not an absolute HTTP URL"},
+			wantCode: exitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+			code, stdout, stderr := runCommand(page, append([]string{"check"}, tt.args...)...)
+			if code != tt.wantCode {
+				t.Errorf("%q. exit code = %d, want %d, stderr %q", tt.name, code, tt.wantCode, stderr)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(stdout, line+"\n") {
+					t.Errorf("%q. check output = %q, want line %q", tt.name, stdout, line)
+				}
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("%q. requests = %d, want %d", tt.name, got, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lukasbob/srcset"
+)
+
+// diffExitTrouble follows diff(1): 0 means no differences, 1 differences,
+// and 2 trouble.
+const diffExitTrouble = 2
+
+func runDiff(e env, args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	attrs := fs.String("attrs", defaultAttrs, "comma-separated `names` of the attributes to compare in HTML documents")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: srcset diff [flags] old new")
+		fmt.Fprintln(e.stderr, "Compares two srcset attribute values, or the srcset attributes of two HTML")
+		fmt.Fprintln(e.stderr, "documents. Exits with 1 if there are differences. Either file may be -.")
+		fs.PrintDefaults()
+	}
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return diffExitTrouble
+	}
+	if len(files) != 2 {
+		fs.Usage()
+		return diffExitTrouble
+	}
+
+	names := splitNames(*attrs)
+	var sides [2][]diffTarget
+	for i, name := range files {
+		data, err := readInput(e, name)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "srcset diff: %v\n", err)
+			return diffExitTrouble
+		}
+		sides[i] = diffTargets(name, string(data), names)
+	}
+
+	fmt.Fprintf(e.stdout, "--- %s\n+++ %s\n", files[0], files[1])
+	if !writeDiff(e.stdout, sides[0], sides[1]) {
+		return exitOK
+	}
+	return exitFailure
+}
+
+// diffTarget is a srcset to compare, and the key to pair it with its
+// counterpart in the other input.
+type diffTarget struct {
+	key   string
+	label string
+	set   srcset.SourceSet
+}
+
+// diffTargets returns the srcset attributes of an HTML document, keyed by
+// their element, attribute and occurrence, or the whole input as a single
+// attribute value.
+func diffTargets(name, data string, names map[string]bool) []diffTarget {
+	if !strings.HasPrefix(strings.TrimSpace(data), "<") {
+		return []diffTarget{{key: "", label: name, set: srcset.Parse(strings.TrimSpace(data))}}
+	}
+
+	var (
+		targets []diffTarget
+		seen    = map[string]int{}
+	)
+	for _, x := range extract(name, data, names) {
+		key := x.Selector + " " + x.Attribute
+		seen[key]++
+		targets = append(targets, diffTarget{
+			key:   key + "#" + strconv.Itoa(seen[key]),
+			label: fmt.Sprintf("%s %s (%s:%d:%d)", x.Selector, x.Attribute, x.File, x.Line, x.Column),
+			set:   x.set,
+		})
+	}
+	return targets
+}
+
+// writeDiff writes the differences between the paired targets to w, and
+// reports whether there were any.
+func writeDiff(w io.Writer, before, after []diffTarget) bool {
+	var (
+		found   bool
+		newKeys = map[string]int{}
+		matched = make([]bool, len(after))
+	)
+	for i, t := range after {
+		newKeys[t.key] = i
+	}
+
+	hunk := func(label string, changes srcset.Changes) {
+		if changes.Empty() {
+			return
+		}
+		found = true
+		fmt.Fprintf(w, "@@ %s\n", label)
+		for _, src := range changes.Removed {
+			fmt.Fprintf(w, "- %s\n", src)
+		}
+		for _, src := range changes.Added {
+			fmt.Fprintf(w, "+ %s\n", src)
+		}
+		for _, c := range changes.Modified {
+			fmt.Fprintf(w, "~ %s -> %s\n", c.Old, c.New)
+		}
+	}
+
+	for _, t := range before {
+		j, ok := newKeys[t.key]
+		if !ok {
+			hunk(t.label+" removed", srcset.Diff(t.set, nil))
+			continue
+		}
+		matched[j] = true
+		hunk(after[j].label, srcset.Diff(t.set, after[j].set))
+	}
+	for j, t := range after {
+		if !matched[j] {
+			hunk(t.label+" added", srcset.Diff(nil, t.set))
+		}
+	}
+
+	return found
+}
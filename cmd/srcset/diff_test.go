@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_diff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+		wantCode int
+	}{
+		{
+			name:     "Equal values",
+			old:      "a.png 1x, b.png 2x\n",
+			new:      "a.png 1x,b.png 2x",
+			want:     "--- old\n+++ new\n",
+			wantCode: exitOK,
+		},
+		{
+			name: "Values",
+			old:  "a.png 320w, b.png 640w, c.png 1280w",
+			new:  "a.png 320w, b2.png 640w, d.png 1920w",
+			want: "--- old\n+++ new\n@@ new\n- c.png 1280w\n+ d.png 1920w\n~ b.png 640w -> b2.png 640w\n",
+
+			wantCode: exitFailure,
+		},
+		{
+			name: "HTML",
+			old:  `<img id=a srcset="a.png 1x"><img id=b srcset="b.png 1x">`,
+			new:  "<img id=a srcset=\"a.png 1x, a2.png 2x\">\n<img id=c srcset=\"c.png\">",
+			want: "--- old\n+++ new\n" +
+				"@@ img#a srcset (new:1:1)\n+ a2.png 2x\n" +
+				"@@ img#b srcset (old:1:29) removed\n- b.png 1x\n" +
+				"@@ img#c srcset (new:2:1) added\n+ c.png\n",
+			wantCode: exitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oldPath, newPath := filepath.Join(dir, "old"), filepath.Join(dir, "new")
+			if err := os.WriteFile(oldPath, []byte(tt.old), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(newPath, []byte(tt.new), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			code, stdout, stderr := runCommand("", "diff", oldPath, newPath)
+			if code != tt.wantCode {
+				t.Errorf("%q. exit code = %d, want %d, stderr %q", tt.name, code, tt.wantCode, stderr)
+			}
+			stdout = strings.ReplaceAll(stdout, dir+string(filepath.Separator), "")
+			if stdout != tt.want {
+				t.Errorf("%q. diff = %q, want %q", tt.name, stdout, tt.want)
+			}
+		})
+	}
+}
+
+func Test_diff_stdin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.txt")
+	if err := os.WriteFile(path, []byte("a.png 2x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, _ := runCommand("a.png 2x", "diff", "-", path); code != exitOK {
+		t.Errorf("exit code = %d, want %d", code, exitOK)
+	}
+}
+
+func Test_diff_trouble(t *testing.T) {
+	for _, args := range [][]string{{"diff", "a"}, {"diff", "does-not-exist", "-"}} {
+		if code, _, _ := runCommand("", args...); code != diffExitTrouble {
+			t.Errorf("%v exit code = %d, want %d", args, code, diffExitTrouble)
+		}
+	}
+}
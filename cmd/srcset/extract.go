@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/lukasbob/srcset"
+)
+
+const defaultAttrs = "srcset,imagesrcset,data-srcset"
+
+// extracted is a srcset attribute found in an HTML document.
+type extracted struct {
+	File       string           `json:"file"`
+	Line       int              `json:"line"`
+	Column     int              `json:"column"`
+	Selector   string           `json:"selector"`
+	Attribute  string           `json:"attribute"`
+	Value      string           `json:"value"`
+	Sizes      string           `json:"sizes,omitempty"`
+	Candidates []jsonCandidate  `json:"candidates"`
+	Warnings   []string         `json:"warnings,omitempty"`
+	set        srcset.SourceSet // the parsed value
+}
+
+type jsonCandidate struct {
+	URL     string   `json:"url"`
+	Width   *int64   `json:"width,omitempty"`
+	Height  *int64   `json:"height,omitempty"`
+	Density *float64 `json:"density,omitempty"`
+}
+
+func runExtract(e env, args []string) int {
+	fs := flag.NewFlagSet("extract", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	format := fs.String("format", "text", "output `format`: text or json")
+	attrs := fs.String("attrs", defaultAttrs, "comma-separated `names` of the attributes to extract")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: srcset extract [flags] [file.html ...]")
+		fmt.Fprintln(e.stderr, "Reads stdin when no file or - is given.")
+		fs.PrintDefaults()
+	}
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(e.stderr, "srcset extract: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	found, err := extractFiles(e, files, splitNames(*attrs))
+	if err != nil {
+		fmt.Fprintf(e.stderr, "srcset extract: %v\n", err)
+		return exitFailure
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(found); err != nil {
+			fmt.Fprintf(e.stderr, "srcset extract: %v\n", err)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	for _, x := range found {
+		fmt.Fprintf(e.stdout, "%s:%d:%d: %s %s=%q\n", x.File, x.Line, x.Column, x.Selector, x.Attribute, x.Value)
+		for _, src := range x.set {
+			fmt.Fprintf(e.stdout, "\t%s\n", src)
+		}
+		for _, w := range x.Warnings {
+			fmt.Fprintf(e.stdout, "\twarning: %s\n", w)
+		}
+	}
+	return exitOK
+}
+
+// splitNames splits a comma-separated list of attribute names into a set.
+func splitNames(list string) map[string]bool {
+	names := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// extractFiles extracts the attributes from the named files, or from stdin
+// if there are none.
+func extractFiles(e env, files []string, names map[string]bool) ([]extracted, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	found := []extracted{}
+	for _, name := range files {
+		data, err := readInput(e, name)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, extract(name, string(data), names)...)
+	}
+	return found, nil
+}
+
+// readInput reads the named file, or stdin for "-".
+func readInput(e env, name string) ([]byte, error) {
+	r, err := openInput(e, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// extract returns the attributes with the given names of the HTML document.
+func extract(file, doc string, names map[string]bool) []extracted {
+	var (
+		found     []extracted
+		z         = html.NewTokenizer(strings.NewReader(doc))
+		offset    = 0
+		positions = srcset.NewPositioner(doc)
+	)
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return found
+		}
+		pos := offset
+		offset += len(z.Raw())
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tok := z.Token()
+		attrs := make(map[string]string, len(tok.Attr))
+		for _, attr := range tok.Attr {
+			if _, ok := attrs[attr.Key]; !ok {
+				attrs[attr.Key] = attr.Val
+			}
+		}
+
+		seen := map[string]bool{}
+		for _, attr := range tok.Attr {
+			// Browsers ignore all but the first attribute with a name.
+			if !names[attr.Key] || seen[attr.Key] {
+				continue
+			}
+			seen[attr.Key] = true
+
+			p := positions.PositionOf(pos)
+			x := extracted{
+				File:      file,
+				Line:      p.Line,
+				Column:    p.Column,
+				Selector:  selector(tok),
+				Attribute: attr.Key,
+				Value:     attr.Val,
+				Sizes:     sizesFor(attr.Key, attrs),
+			}
+			x.set = srcset.Parse(attr.Val, srcset.WithWarningHandler(func(w srcset.Warning) {
+				x.Warnings = append(x.Warnings, w.String())
+			}))
+			x.Candidates = make([]jsonCandidate, len(x.set))
+			for i, src := range x.set {
+				x.Candidates[i] = jsonCandidate{URL: src.URL, Width: src.Width, Height: src.Height, Density: src.Density}
+			}
+			found = append(found, x)
+		}
+	}
+}
+
+// sizesFor returns the sizes attribute that goes with the srcset attribute
+// name, such as data-sizes for data-srcset.
+func sizesFor(name string, attrs map[string]string) string {
+	switch name {
+	case "imagesrcset":
+		return attrs["imagesizes"]
+	case "srcset":
+		return attrs["sizes"]
+	}
+	if prefix := strings.TrimSuffix(name, "srcset"); prefix != name {
+		if sizes, ok := attrs[prefix+"sizes"]; ok {
+			return sizes
+		}
+	}
+	return attrs["sizes"]
+}
+
+// selector returns a CSS selector that describes the element, such as
+// img#hero.wide.
+func selector(tok html.Token) string {
+	var sb strings.Builder
+	sb.WriteString(tok.Data)
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "id":
+			if attr.Val != "" {
+				sb.WriteString("#" + attr.Val)
+			}
+		case "class":
+			for _, class := range strings.Fields(attr.Val) {
+				sb.WriteString("." + class)
+			}
+		}
+	}
+	return sb.String()
+}
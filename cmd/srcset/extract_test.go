@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const page = `<!DOCTYPE html>
+<picture>
+  <source type="image/webp" srcset="a.webp 1x, b.webp 2x">
+  <img id="hero" class="wide full" src="a.png"
+       srcset="a.png 320w, b.png 640w, c.png 1q" sizes="50vw">
+</picture>
+<img data-srcset="lazy.png 1x" data-sizes="auto" srcset="ignored.png 9x">
+`
+
+// runCommand runs the command line with stdin and returns the exit code and
+// output.
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_extract_text(t *testing.T) {
+	code, stdout, stderr := runCommand(page, "extract", "-attrs", "srcset", "-")
+	if code != exitOK {
+		t.Fatalf("extract exit code = %d, stderr %q", code, stderr)
+	}
+
+	want := `-:3:3: source srcset="a.webp 1x, b.webp 2x"
+	a.webp 1x
+	b.webp 2x
+-:4:3: img#hero.wide.full srcset="a.png 320w, b.png 640w, c.png 1q"
+	a.png 320w
+	b.png 640w
+	warning: dropped candidate at 1:25: invalid descriptors ("c.png 1q")
+-:7:1: img srcset="ignored.png 9x"
+	ignored.png 9x
+`
+	if stdout != want {
+		t.Errorf("extract output = %q, want %q", stdout, want)
+	}
+}
+
+func Test_extract_json(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(path, []byte(page), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCommand("", "extract", "-format", "json", path)
+	if code != exitOK {
+		t.Fatalf("extract exit code = %d, stderr %q", code, stderr)
+	}
+
+	var got []extracted
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("extract found %d attributes, want 4", len(got))
+	}
+
+	lazy := got[2]
+	if lazy.File != path || lazy.Attribute != "data-srcset" || lazy.Sizes != "auto" || lazy.Line != 7 {
+		t.Errorf("extract = %+v, want data-srcset at line 7 with sizes auto", lazy)
+	}
+	if hero := got[1]; hero.Sizes != "50vw" || len(hero.Candidates) != 2 || *hero.Candidates[1].Width != 640 || len(hero.Warnings) != 1 {
+		t.Errorf("extract = %+v, want two candidates and one warning", hero)
+	}
+}
+
+func Test_extract_usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "Unknown format", args: []string{"extract", "-format", "xml"}, want: exitUsage},
+		{name: "Unknown flag", args: []string{"extract", "-x"}, want: exitUsage},
+		{name: "Missing file", args: []string{"extract", "does-not-exist.html"}, want: exitFailure},
+		{name: "Unknown command", args: []string{"nope"}, want: exitUsage},
+		{name: "No command", args: nil, want: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := runCommand("", tt.args...); code != tt.want {
+				t.Errorf("%q. exit code = %d, want %d", tt.name, code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lukasbob/srcset"
+	"github.com/lukasbob/srcset/respimg"
+)
+
+func runGenerate(e env, args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	prefix := fs.String("url-prefix", "", "`prefix` of the candidate URLs, followed by the file names")
+	markup := fs.String("markup", "", "also render `element`: img or picture")
+	sizes := fs.String("sizes", "100vw", "sizes `value` of the rendered markup")
+	alt := fs.String("alt", "", "alt `text` of the rendered markup")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: srcset generate [flags] image ...")
+		fmt.Fprintln(e.stderr, "Images may be given as glob patterns, such as 'images/hero-*.jpg'.")
+		fs.PrintDefaults()
+	}
+	patterns, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(patterns) == 0 || (*markup != "" && *markup != "img" && *markup != "picture") {
+		fs.Usage()
+		return exitUsage
+	}
+
+	paths, err := expandGlobs(patterns)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "srcset generate: %v\n", err)
+		return exitFailure
+	}
+
+	set, err := respimg.FromFiles(paths, func(p string) string {
+		if *prefix == "" {
+			return filepath.ToSlash(p)
+		}
+		return strings.TrimSuffix(*prefix, "/") + "/" + path.Base(filepath.ToSlash(p))
+	})
+	if err != nil {
+		fmt.Fprintf(e.stderr, "srcset generate: %v\n", err)
+		return exitFailure
+	}
+
+	if *markup == "" {
+		fmt.Fprintln(e.stdout, set)
+		return exitOK
+	}
+
+	opts := srcset.ImgOptions{Sizes: srcset.ParseSizes(*sizes), Alt: *alt}
+	var out template.HTML
+	if *markup == "picture" {
+		out, err = srcset.RenderPicture(set.GroupByType(), opts)
+	} else {
+		out, err = srcset.RenderImg(set, opts)
+	}
+	if err != nil {
+		fmt.Fprintf(e.stderr, "srcset generate: %v\n", err)
+		return exitFailure
+	}
+	fmt.Fprintln(e.stdout, out)
+	return exitOK
+}
+
+// expandGlobs expands the patterns among args, keeping the other arguments,
+// so that patterns work when the shell does not expand them.
+func expandGlobs(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, "*?[") {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeImages writes images of the given widths to dir, as PNG for the
+// names ending in .png and JPEG otherwise.
+func writeImages(t *testing.T, dir string, widths map[string]int) {
+	t.Helper()
+	for name, width := range widths {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img := image.NewGray(image.Rect(0, 0, width, 1))
+		if filepath.Ext(name) == ".png" {
+			err = png.Encode(f, img)
+		} else {
+			err = jpeg.Encode(f, img, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+}
+
+func Test_generate(t *testing.T) {
+	dir := t.TempDir()
+	writeImages(t, dir, map[string]int{"hero-640.jpg": 640, "hero-320.jpg": 320, "hero-640.png": 640})
+	pattern := filepath.Join(dir, "hero-*.jpg")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "Srcset",
+			args: []string{"-url-prefix", "/static/", pattern},
+			want: "/static/hero-320.jpg 320w, /static/hero-640.jpg 640w\n",
+		},
+		{
+			name: "Img",
+			args: []string{"-url-prefix", "/static", "-markup", "img", "-sizes", "50vw", "-alt", "Hero", pattern},
+			want: `<img src="/static/hero-640.jpg" srcset="/static/hero-320.jpg 320w, /static/hero-640.jpg 640w" sizes="50vw" alt="Hero">` + "\n",
+		},
+		{
+			name: "Picture",
+			args: []string{"-url-prefix", "/static/", "-markup", "picture", filepath.Join(dir, "hero-640.png"), pattern},
+			want: `<picture><source type="image/png" srcset="/static/hero-640.png 640w" sizes="100vw">` +
+				`<img src="/static/hero-640.jpg" srcset="/static/hero-320.jpg 320w, /static/hero-640.jpg 640w" sizes="100vw" alt=""></picture>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCommand("", append([]string{"generate"}, tt.args...)...)
+			if code != exitOK {
+				t.Fatalf("%q. exit code = %d, stderr %q", tt.name, code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("%q. generate = %q, want %q", tt.name, stdout, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generate_errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "No images", args: nil, want: exitUsage},
+		{name: "Unknown markup", args: []string{"-markup", "video", "a.jpg"}, want: exitUsage},
+		{name: "No matches", args: []string{filepath.Join(dir, "*.jpg")}, want: exitFailure},
+		{name: "Missing file", args: []string{filepath.Join(dir, "a.jpg")}, want: exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := runCommand("", append([]string{"generate"}, tt.args...)...); code != tt.want {
+				t.Errorf("%q. exit code = %d, want %d", tt.name, code, tt.want)
+			}
+		})
+	}
+}
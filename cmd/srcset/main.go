@@ -0,0 +1,112 @@
+// Command srcset inspects and transforms the srcset attributes of HTML
+// documents.
+//
+// Usage:
+//
+//	srcset <command> [flags] [arguments]
+//
+// Run "srcset <command> -h" for the flags of a command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// env holds the standard streams of a command, so that commands can be
+// tested without a process.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command runs with the arguments after its name, and returns the exit code.
+type command struct {
+	summary string
+	run     func(e env, args []string) int
+}
+
+// Exit codes shared by the commands.
+const (
+	exitOK      = 0
+	exitFailure = 1 // problems or differences were found
+	exitUsage   = 2
+)
+
+var commands = map[string]command{
+	"check":    {"request every candidate and report broken ones", runCheck},
+	"diff":     {"compare srcset attributes", runDiff},
+	"extract":  {"print the srcset attributes of HTML documents", runExtract},
+	"generate": {"build a srcset from image files", runGenerate},
+	"rewrite":  {"resolve or rebase the candidate URLs of HTML documents", runRewrite},
+	"serve":    {"serve a JSON API for parsing and selecting candidates", runServe},
+}
+
+func main() {
+	os.Exit(run(env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:]))
+}
+
+func run(e env, args []string) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] != "-h" && args[0] != "-help" && args[0] != "help" {
+			fmt.Fprintf(e.stderr, "srcset: unknown command %q\n", args[0])
+		}
+		usage(e.stderr)
+		return exitUsage
+	}
+	return cmd.run(e, args[1:])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: srcset <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// parseFlags parses args with fs and returns the arguments, allowing flags
+// after them, as in "srcset check page.html -concurrency 16". Arguments
+// after "--" are never parsed as flags.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		remaining := fs.Args()
+		if consumed := len(args) - len(remaining); consumed > 0 && args[consumed-1] == "--" {
+			return append(rest, remaining...), nil
+		}
+		if len(remaining) == 0 {
+			return rest, nil
+		}
+		rest = append(rest, remaining[0])
+		args = remaining[1:]
+	}
+}
+
+// openInput opens the named file for reading, or stdin for "-".
+func openInput(e env, name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(e.stdin), nil
+	}
+	return os.Open(name)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/lukasbob/srcset"
+)
+
+func runRewrite(e env, args []string) int {
+	fs := flag.NewFlagSet("rewrite", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	base := fs.String("base", "", "`URL` to resolve relative candidate URLs against")
+	from := fs.String("from", "", "rebase absolute candidate URLs starting with this `prefix` onto -base")
+	write := fs.Bool("w", false, "write the result to the files instead of stdout")
+	attrs := fs.String("attrs", defaultAttrs, "comma-separated `names` of the attributes to rewrite")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: srcset rewrite -base URL [flags] [file.html ...]")
+		fmt.Fprintln(e.stderr, "Reads stdin when no file or - is given.")
+		fs.PrintDefaults()
+	}
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	baseURL, err := url.Parse(*base)
+	if *base == "" || err != nil || !baseURL.IsAbs() {
+		fmt.Fprintln(e.stderr, "srcset rewrite: -base must be an absolute URL")
+		return exitUsage
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var names []string
+	for name := range splitNames(*attrs) {
+		names = append(names, name)
+	}
+
+	rebase := func(src srcset.ImageSource) srcset.ImageSource {
+		src.URL = rebaseURL(src.URL, baseURL, *from)
+		return src
+	}
+
+	for _, name := range files {
+		data, err := readInput(e, name)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "srcset rewrite: %v\n", err)
+			return exitFailure
+		}
+
+		var out bytes.Buffer
+		if err := srcset.RewriteHTML(bytes.NewReader(data), &out, rebase, srcset.WithAttributeNames(srcset.AttributeNames{Srcset: names})); err != nil {
+			fmt.Fprintf(e.stderr, "srcset rewrite: %s: %v\n", name, err)
+			return exitFailure
+		}
+
+		if *write && name != "-" {
+			if err := writeFile(name, out.Bytes()); err != nil {
+				fmt.Fprintf(e.stderr, "srcset rewrite: %v\n", err)
+				return exitFailure
+			}
+			continue
+		}
+		if _, err := e.stdout.Write(out.Bytes()); err != nil {
+			fmt.Fprintf(e.stderr, "srcset rewrite: %v\n", err)
+			return exitFailure
+		}
+	}
+	return exitOK
+}
+
+// rebaseURL resolves a relative raw URL against base. An absolute URL that
+// starts with from is moved onto base instead. Other URLs, and data URLs,
+// are returned unchanged.
+func rebaseURL(raw string, base *url.URL, from string) string {
+	if strings.HasPrefix(strings.ToLower(raw), "data:") {
+		return raw
+	}
+	if from != "" && strings.HasPrefix(raw, from) {
+		raw = strings.TrimPrefix(strings.TrimPrefix(raw, from), "/")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.IsAbs() {
+		return raw
+	}
+	return base.ResolveReference(u).String()
+}
+
+// writeFile replaces the contents of the named file, keeping its mode.
+func writeFile(name string, data []byte) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, info.Mode().Perm())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_rewrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		want  string
+	}{
+		{
+			name:  "Relative URLs",
+			args:  []string{"-base", "https://cdn.example.com/static/"},
+			input: `<p>a.png</p><img src=a.png srcset="a.png 1x, /b.png 2x, https://other.example/c.png 3x">`,
+			want:  `<p>a.png</p><img src="a.png" srcset="https://cdn.example.com/static/a.png 1x, https://cdn.example.com/b.png 2x, https://other.example/c.png 3x">`,
+		},
+		{
+			name:  "Rebase",
+			args:  []string{"-base", "https://staging.example.com/", "-from", "https://www.example.com"},
+			input: `<img srcset="https://www.example.com/a.png 100w, data:image/png;base64,AAAA 200w">`,
+			want:  `<img srcset="https://staging.example.com/a.png 100w, data:image/png;base64,AAAA 200w">`,
+		},
+		{
+			name:  "Untouched markup",
+			args:  []string{"-base", "https://cdn.example.com/"},
+			input: `<A HREF="/p?a=1&amp;b=2">A &amp; B</A><IMG SRC="a.png" ALT="&quot;x&quot;">`,
+			want:  `<A HREF="/p?a=1&amp;b=2">A &amp; B</A><IMG SRC="a.png" ALT="&quot;x&quot;">`,
+		},
+		{
+			name:  "Attributes",
+			args:  []string{"-base", "https://cdn.example.com/", "-attrs", "data-srcset"},
+			input: `<img srcset="a.png" data-srcset="b.png">`,
+			want:  `<img srcset="a.png" data-srcset="https://cdn.example.com/b.png">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCommand(tt.input, append([]string{"rewrite"}, tt.args...)...)
+			if code != exitOK {
+				t.Fatalf("%q. exit code = %d, stderr %q", tt.name, code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("%q. rewrite = %q, want %q", tt.name, stdout, tt.want)
+			}
+		})
+	}
+}
+
+func Test_rewrite_inPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(path, []byte(`<img srcset="a.png 1x">`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCommand("", "rewrite", "-w", "-base", "https://cdn.example.com/", path)
+	if code != exitOK || stdout != "" {
+		t.Fatalf("exit code = %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<img srcset="https://cdn.example.com/a.png 1x">`; string(got) != want {
+		t.Errorf("rewritten file = %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("rewritten file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func Test_rewrite_usage(t *testing.T) {
+	for _, args := range [][]string{{"rewrite"}, {"rewrite", "-base", "/relative/"}} {
+		if code, _, _ := runCommand("", args...); code != exitUsage {
+			t.Errorf("%v exit code = %d, want %d", args, code, exitUsage)
+		}
+	}
+}
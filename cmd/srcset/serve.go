@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/lukasbob/srcset"
+)
+
+// maxRequestBytes limits the size of request bodies of the HTTP API.
+const maxRequestBytes = 1 << 20
+
+func runServe(e env, args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	listen := fs.String("listen", ":8080", "`address` to listen on")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: srcset serve [flags]")
+		fmt.Fprintln(e.stderr, "Serves a JSON API:")
+		fmt.Fprintln(e.stderr, "  POST /v1/parse   {\"srcset\": ..., \"sizes\": ...}")
+		fmt.Fprintln(e.stderr, "  POST /v1/select  {\"srcset\": ..., \"sizes\": ..., \"viewports\": [{\"width\": ..., \"height\": ..., \"dpr\": ...}]}")
+		fmt.Fprintln(e.stderr, "  POST /v1/html    an HTML document")
+		fs.PrintDefaults()
+	}
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(rest) > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           newAPI(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(e.stderr, "srcset serve: listening on %s\n", *listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(e.stderr, "srcset serve: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// newAPI returns the handler of the HTTP API.
+func newAPI() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/parse", post(handleParse))
+	mux.HandleFunc("/v1/select", post(handleSelect))
+	mux.HandleFunc("/v1/html", post(handleHTML))
+	return mux
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type jsonWarning struct {
+	Kind    string `json:"kind"`
+	Offset  int    `json:"offset"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Text    string `json:"text"`
+	Message string `json:"message"`
+}
+
+type jsonViolation struct {
+	Offset  int    `json:"offset"`
+	URL     string `json:"url"`
+	Message string `json:"message"`
+}
+
+// parseResult is the response of /v1/parse, and part of the response of
+// /v1/html.
+type parseResult struct {
+	Candidates []jsonCandidate `json:"candidates"`
+	Sizes      string          `json:"sizes,omitempty"`
+	Warnings   []jsonWarning   `json:"warnings"`
+	Violations []jsonViolation `json:"violations"`
+	Lint       []jsonViolation `json:"lint"`
+}
+
+type parseRequest struct {
+	Srcset string `json:"srcset"`
+	Sizes  string `json:"sizes"`
+}
+
+type viewport struct {
+	Name   string  `json:"name,omitempty"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height,omitempty"`
+	DPR    float64 `json:"dpr"`
+}
+
+type selectRequest struct {
+	parseRequest
+	Viewports []viewport `json:"viewports"`
+}
+
+type selection struct {
+	viewport
+	SourceSize float64 `json:"sourceSize"`
+	URL        string  `json:"url,omitempty"`
+}
+
+type htmlImage struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Selector  string `json:"selector"`
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"`
+	parseResult
+}
+
+// post restricts h to POST requests and limits the size of their bodies.
+func post(h func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, apiError{"method not allowed"})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+		h(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"invalid request: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func handleParse(w http.ResponseWriter, r *http.Request) {
+	var req parseRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	writeJSON(w, http.StatusOK, parseValue(req.Srcset, req.Sizes))
+}
+
+func handleSelect(w http.ResponseWriter, r *http.Request) {
+	var req selectRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	viewports := req.Viewports
+	if len(viewports) == 0 {
+		for _, d := range srcset.Devices {
+			viewports = append(viewports, viewport{Name: d.Name, Width: d.ViewportWidth, Height: d.ViewportHeight, DPR: d.DPR})
+		}
+	}
+
+	var (
+		set        = srcset.Parse(req.Srcset)
+		sizes      = srcset.ParseSizes(req.Sizes)
+		selections = make([]selection, 0, len(viewports))
+	)
+	for _, vp := range viewports {
+		if vp.Width <= 0 || vp.DPR <= 0 {
+			writeJSON(w, http.StatusBadRequest, apiError{"viewports need a positive width and dpr"})
+			return
+		}
+		ctx := srcset.EvalContext{ViewportWidth: vp.Width, ViewportHeight: vp.Height}
+		sel := selection{viewport: vp, SourceSize: sizes.EvaluateContext(ctx)}
+		if src, ok := set.BestForContext(ctx, vp.DPR, sizes); ok {
+			sel.URL = src.URL
+		}
+		selections = append(selections, sel)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Selections []selection `json:"selections"`
+	}{selections})
+}
+
+func handleHTML(w http.ResponseWriter, r *http.Request) {
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, r.Body); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"invalid request: " + err.Error()})
+		return
+	}
+
+	images := []htmlImage{}
+	for _, x := range extract("", sb.String(), splitNames(defaultAttrs)) {
+		images = append(images, htmlImage{
+			Line:        x.Line,
+			Column:      x.Column,
+			Selector:    x.Selector,
+			Attribute:   x.Attribute,
+			Value:       x.Value,
+			parseResult: parseValue(x.Value, x.Sizes),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Images []htmlImage `json:"images"`
+	}{images})
+}
+
+// parseValue parses, validates and lints a srcset attribute value.
+func parseValue(value, sizes string) parseResult {
+	res := parseResult{
+		Warnings:   []jsonWarning{},
+		Violations: []jsonViolation{},
+		Lint:       []jsonViolation{},
+	}
+
+	set := srcset.Parse(value, srcset.WithWarningHandler(func(w srcset.Warning) {
+		res.Warnings = append(res.Warnings, jsonWarning{
+			Kind:    w.Kind.String(),
+			Offset:  w.Offset,
+			Line:    w.Line,
+			Column:  w.Column,
+			Text:    w.Text,
+			Message: w.Message,
+		})
+	}))
+	res.Candidates = make([]jsonCandidate, len(set))
+	for i, src := range set {
+		res.Candidates[i] = jsonCandidate{URL: src.URL, Width: src.Width, Height: src.Height, Density: src.Density}
+	}
+	if sizes != "" {
+		res.Sizes = srcset.ParseSizes(sizes).String()
+	}
+	for _, v := range set.Validate() {
+		res.Violations = append(res.Violations, jsonViolation{Offset: v.Offset, URL: v.URL, Message: v.Message})
+	}
+	for _, v := range set.Lint() {
+		res.Lint = append(res.Lint, jsonViolation{Offset: v.Offset, URL: v.URL, Message: v.Message})
+	}
+	return res
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_api(t *testing.T) {
+	srv := httptest.NewServer(newAPI())
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		want       string // a substring of the response
+	}{
+		{
+			name:       "Parse",
+			method:     http.MethodPost,
+			path:       "/v1/parse",
+			body:       `{"srcset": "a.png 320w, //cdn.example.com/b.png 640w, c.png 1q", "sizes": "(min-width: 800px)  50vw, 100vw"}`,
+			wantStatus: http.StatusOK,
+			want:       `"candidates":[{"url":"a.png","width":320},{"url":"//cdn.example.com/b.png","width":640}],"sizes":"(min-width: 800px) 50vw, 100vw","warnings":[{"kind":"dropped candidate","offset":42,"line":1,"column":43,"text":"c.png 1q","message":"invalid descriptors"}],"violations":[],"lint":[{"offset":12,"url":"//cdn.example.com/b.png","message":"protocol-relative URL"}]`,
+		},
+		{
+			name:       "Select",
+			method:     http.MethodPost,
+			path:       "/v1/select",
+			body:       `{"srcset": "a.png 320w, b.png 640w", "sizes": "50vw", "viewports": [{"width": 640, "dpr": 1}, {"width": 640, "dpr": 2}]}`,
+			wantStatus: http.StatusOK,
+			want:       `{"selections":[{"width":640,"dpr":1,"sourceSize":320,"url":"a.png"},{"width":640,"dpr":2,"sourceSize":320,"url":"b.png"}]}`,
+		},
+		{
+			name:       "Select on devices",
+			method:     http.MethodPost,
+			path:       "/v1/select",
+			body:       `{"srcset": "a.png 320w, b.png 640w"}`,
+			wantStatus: http.StatusOK,
+			want:       `{"name":"iPhone 15","width":393,"height":852,"dpr":3,"sourceSize":393,"url":"b.png"}`,
+		},
+		{
+			name:       "HTML",
+			method:     http.MethodPost,
+			path:       "/v1/html",
+			body:       "<p>\n<img class=hero srcset=\"a.png 1x, b.png 1x\">",
+			wantStatus: http.StatusOK,
+			want:       `{"images":[{"line":2,"column":1,"selector":"img.hero","attribute":"srcset","value":"a.png 1x, b.png 1x","candidates":[{"url":"a.png","density":1},{"url":"b.png","density":1}],"warnings":[],"violations":[{"offset":10,"url":"b.png","message":"duplicate density 1x"}],"lint":[]}]}`,
+		},
+		{
+			name:       "Invalid viewport",
+			method:     http.MethodPost,
+			path:       "/v1/select",
+			body:       `{"srcset": "a.png", "viewports": [{"width": 0, "dpr": 1}]}`,
+			wantStatus: http.StatusBadRequest,
+			want:       `"error"`,
+		},
+		{
+			name:       "Invalid JSON",
+			method:     http.MethodPost,
+			path:       "/v1/parse",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			want:       `"error":"invalid request`,
+		},
+		{
+			name:       "Wrong method",
+			method:     http.MethodGet,
+			path:       "/v1/parse",
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       `"error":"method not allowed"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var body json.RawMessage
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("%q. status = %d, want %d", tt.name, resp.StatusCode, tt.wantStatus)
+			}
+			if !strings.Contains(string(body), tt.want) {
+				t.Errorf("%q. response = %s, want %s", tt.name, body, tt.want)
+			}
+		})
+	}
+}
+
+func Test_serve_usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Help", args: []string{"serve", "-h"}},
+		{name: "Unknown flag", args: []string{"serve", "-x"}},
+		{name: "Arguments", args: []string{"serve", "page.html"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCommand("", tt.args...)
+			if code != exitUsage {
+				t.Errorf("%q. exit code = %d, want %d", tt.name, code, exitUsage)
+			}
+			if !strings.Contains(stderr, "usage: srcset serve") {
+				t.Errorf("%q. stderr = %q, want serve usage", tt.name, stderr)
+			}
+		})
+	}
+}
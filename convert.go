@@ -0,0 +1,88 @@
+package srcset
+
+import (
+	"math"
+	"strconv"
+)
+
+// ToDensitySet converts width candidates to density candidates for an image
+// displayed in a slot of the given width in CSS pixels, for clients that do
+// not support width descriptors. Densities are rounded to three decimals,
+// and at least 0.001, so that narrow candidates do not become 0x. Height
+// descriptors are removed from converted candidates, and other candidates
+// are kept unchanged. If slotWidthPx is not positive, width candidates are
+// dropped.
+func (s SourceSet) ToDensitySet(slotWidthPx float64) SourceSet {
+	converted := make(SourceSet, 0, len(s))
+	for _, src := range s {
+		src = src.Clone()
+		if src.Width != nil {
+			if slotWidthPx <= 0 {
+				continue
+			}
+			d := math.Max(1, math.Round(float64(*src.Width)/slotWidthPx*1000)) / 1000
+			src.Width, src.Height, src.Density = nil, nil, &d
+		}
+		converted = append(converted, src)
+	}
+	return converted
+}
+
+// ToWidthSet converts density candidates, and candidates without
+// descriptors, to width candidates for an image that is baseWidth pixels
+// wide at 1x. Widths are rounded to whole pixels, and at least 1, and other
+// candidates are kept unchanged. It also returns a sizes value for the
+// converted set, which displays the image at its 1x width, or narrower on
+// small viewports. If baseWidth is not positive, a copy of s is returned
+// unchanged, without sizes.
+func (s SourceSet) ToWidthSet(baseWidth int64) (SourceSet, SizeList) {
+	if baseWidth <= 0 {
+		return s.Clone(), nil
+	}
+
+	converted := make(SourceSet, len(s))
+	for i, src := range s {
+		src = src.Clone()
+		if src.Width == nil {
+			w := int64(math.Max(1, math.Round(float64(baseWidth)*src.Candidate().density())))
+			src.Density, src.Width = nil, &w
+		}
+		converted[i] = src
+	}
+
+	px := strconv.FormatInt(baseWidth, 10) + "px"
+	return converted, ParseSizes("(max-width: " + px + ") 100vw, " + px)
+}
+
+// Scale multiplies the descriptors of every candidate by factor: widths and
+// heights are rounded to whole pixels, and at least 1, while densities are
+// rounded to three decimals. Candidates without descriptors get a density of
+// factor. If factor is not positive, a copy of s is returned unchanged.
+func (s SourceSet) Scale(factor float64) SourceSet {
+	scaled := s.Clone()
+	if factor <= 0 {
+		return scaled
+	}
+
+	scale := func(v int64) *int64 {
+		r := int64(math.Max(1, math.Round(float64(v)*factor)))
+		return &r
+	}
+
+	for i, src := range scaled {
+		switch {
+		case src.Width != nil || src.Height != nil:
+			if src.Width != nil {
+				src.Width = scale(*src.Width)
+			}
+			if src.Height != nil {
+				src.Height = scale(*src.Height)
+			}
+		default:
+			d := math.Round(src.Candidate().density()*factor*1000) / 1000
+			src.Density = &d
+		}
+		scaled[i] = src
+	}
+	return scaled
+}
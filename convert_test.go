@@ -0,0 +1,80 @@
+package srcset
+
+import "testing"
+
+func Test_ToDensitySet(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		slot  float64
+		want  string
+	}{
+		{name: "Widths", input: "a.png 400w, b.png 800w, c.png 1200w", slot: 400, want: "a.png 1x, b.png 2x, c.png 3x"},
+		{name: "Rounded", input: "a.png 500w", slot: 300, want: "a.png 1.667x"},
+		{name: "Rounded to minimum", input: "a.png 1w, b.png 2000w", slot: 4000, want: "a.png 0.001x, b.png 0.5x"},
+		{name: "Height removed", input: "a.png 800w 600h", slot: 400, want: "a.png 2x"},
+		{name: "Densities kept", input: "a.png, b.png 2x", slot: 400, want: "a.png, b.png 2x"},
+		{name: "Invalid slot", input: "a.png 400w, b.png 2x", slot: 0, want: "b.png 2x"},
+		{name: "Empty", input: "", slot: 400, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).ToDensitySet(tt.slot).String(); got != tt.want {
+				t.Errorf("%q. ToDensitySet() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ToWidthSet(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		base      int64
+		want      string
+		wantSizes string
+	}{
+		{name: "Densities", input: "a.png, b.png 1.5x, c.png 2x", base: 400, want: "a.png 400w, b.png 600w, c.png 800w", wantSizes: "(max-width: 400px) 100vw, 400px"},
+		{name: "Rounded", input: "a.png 1.333x", base: 300, want: "a.png 400w", wantSizes: "(max-width: 300px) 100vw, 300px"},
+		{name: "Widths kept", input: "a.png 320w, b.png 2x", base: 320, want: "a.png 320w, b.png 640w", wantSizes: "(max-width: 320px) 100vw, 320px"},
+		{name: "Rounded to 1", input: "a.png 0.001x", base: 100, want: "a.png 1w", wantSizes: "(max-width: 100px) 100vw, 100px"},
+		{name: "Invalid base width", input: "a.png 320w, b.png 2x", base: 0, want: "a.png 320w, b.png 2x", wantSizes: ""},
+		{name: "Negative base width", input: "a.png", base: -100, want: "a.png", wantSizes: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sizes := Parse(tt.input).ToWidthSet(tt.base)
+			if got.String() != tt.want {
+				t.Errorf("%q. ToWidthSet() = %q, want %q", tt.name, got, tt.want)
+			}
+			if sizes.String() != tt.wantSizes {
+				t.Errorf("%q. ToWidthSet() sizes = %q, want %q", tt.name, sizes, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func Test_Scale(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		factor float64
+		want   string
+	}{
+		{name: "Widths", input: "a.png 400w, b.png 801w", factor: 0.5, want: "a.png 200w, b.png 401w"},
+		{name: "Width and height", input: "a.png 400w 300h", factor: 2, want: "a.png 800w 600h"},
+		{name: "Densities", input: "a.png, b.png 1.5x", factor: 0.5, want: "a.png 0.5x, b.png 0.75x"},
+		{name: "Rounded to 1", input: "a.png 1w", factor: 0.1, want: "a.png 1w"},
+		{name: "Invalid factor", input: "a.png 400w", factor: 0, want: "a.png 400w"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).Scale(tt.factor).String(); got != tt.want {
+				t.Errorf("%q. Scale() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
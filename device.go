@@ -0,0 +1,50 @@
+package srcset
+
+// Device describes the viewport and device pixel ratio of a device, in CSS
+// pixels.
+type Device struct {
+	Name           string
+	ViewportWidth  float64
+	ViewportHeight float64
+	DPR            float64
+}
+
+// Common devices, in portrait orientation for phones and tablets.
+var (
+	IPhoneSE        = Device{Name: "iPhone SE", ViewportWidth: 375, ViewportHeight: 667, DPR: 2}
+	IPhone15        = Device{Name: "iPhone 15", ViewportWidth: 393, ViewportHeight: 852, DPR: 3}
+	IPhone15ProMax  = Device{Name: "iPhone 15 Pro Max", ViewportWidth: 430, ViewportHeight: 932, DPR: 3}
+	MidRangeAndroid = Device{Name: "Mid-range Android", ViewportWidth: 360, ViewportHeight: 800, DPR: 2}
+	Pixel8          = Device{Name: "Pixel 8", ViewportWidth: 412, ViewportHeight: 915, DPR: 2.625}
+	IPadAir         = Device{Name: "iPad Air", ViewportWidth: 820, ViewportHeight: 1180, DPR: 2}
+	Laptop          = Device{Name: "Laptop", ViewportWidth: 1366, ViewportHeight: 768, DPR: 1}
+	MacBookAir      = Device{Name: "MacBook Air", ViewportWidth: 1470, ViewportHeight: 956, DPR: 2}
+	Desktop         = Device{Name: "Desktop", ViewportWidth: 1920, ViewportHeight: 1080, DPR: 1}
+	Desktop4K       = Device{Name: "4K desktop", ViewportWidth: 1920, ViewportHeight: 1080, DPR: 2}
+)
+
+// Devices lists the common devices, from the narrowest viewport to the
+// widest.
+var Devices = []Device{
+	MidRangeAndroid,
+	IPhoneSE,
+	IPhone15,
+	Pixel8,
+	IPhone15ProMax,
+	IPadAir,
+	Laptop,
+	MacBookAir,
+	Desktop,
+	Desktop4K,
+}
+
+// EvalContext returns the context to evaluate sizes for the device.
+func (d Device) EvalContext() EvalContext {
+	return EvalContext{ViewportWidth: d.ViewportWidth, ViewportHeight: d.ViewportHeight}
+}
+
+// SelectForDevice returns the candidate a browser on the device would pick,
+// or false if there is none.
+func SelectForDevice(set SourceSet, sizes SizeList, d Device) (ImageSource, bool) {
+	return set.BestForContext(d.EvalContext(), d.DPR, sizes)
+}
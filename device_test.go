@@ -0,0 +1,37 @@
+package srcset
+
+import "testing"
+
+func Test_SelectForDevice(t *testing.T) {
+	set := Parse("s.png 480w, m.png 960w, l.png 1440w, xl.png 2880w, xxl.png 3840w")
+
+	tests := []struct {
+		name   string
+		sizes  string
+		device Device
+		want   string
+	}{
+		{name: "Phone, full width", sizes: "100vw", device: IPhone15, want: "l.png"},
+		{name: "Mid-range Android, full width", sizes: "100vw", device: MidRangeAndroid, want: "m.png"},
+		{name: "Laptop, half width", sizes: "(min-width: 1024px) 50vw, 100vw", device: Laptop, want: "m.png"},
+		{name: "4K desktop, full width", sizes: "100vw", device: Desktop4K, want: "xxl.png"},
+		{name: "Tablet, viewport height", sizes: "50vh", device: IPadAir, want: "l.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SelectForDevice(set, ParseSizes(tt.sizes), tt.device)
+			if !ok || got.URL != tt.want {
+				t.Errorf("%q. SelectForDevice() = %q, %v, want %q", tt.name, got.URL, ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Devices(t *testing.T) {
+	for i := 1; i < len(Devices); i++ {
+		if Devices[i].ViewportWidth < Devices[i-1].ViewportWidth {
+			t.Errorf("Devices[%d] = %s is narrower than %s", i, Devices[i].Name, Devices[i-1].Name)
+		}
+	}
+}
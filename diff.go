@@ -0,0 +1,75 @@
+package srcset
+
+// Change describes a candidate that is present in both sets of a Diff, but
+// with a different URL or different descriptors.
+type Change struct {
+	Old ImageSource
+	New ImageSource
+}
+
+// Changes is the result of Diff.
+type Changes struct {
+	Added    []ImageSource
+	Removed  []ImageSource
+	Modified []Change
+}
+
+// Empty reports whether no differences were found.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// Diff reports the differences between a and b. Candidates are first matched
+// by URL, and the remaining candidates by their descriptors, so that both a
+// changed descriptor and a changed URL are reported as a modification.
+// Candidates that cannot be matched are reported as added or removed.
+func Diff(a, b SourceSet) Changes {
+	var (
+		changes  Changes
+		matchedA = make([]bool, len(a))
+		matchedB = make([]bool, len(b))
+	)
+
+	match := func(key func(ImageSource) string) {
+		index := map[string]int{}
+		for j := len(b) - 1; j >= 0; j-- {
+			if !matchedB[j] {
+				index[key(b[j])] = j
+			}
+		}
+		for i, src := range a {
+			if matchedA[i] {
+				continue
+			}
+			j, ok := index[key(src)]
+			if !ok || matchedB[j] {
+				continue
+			}
+			matchedA[i], matchedB[j] = true, true
+			if !src.Equal(b[j]) {
+				changes.Modified = append(changes.Modified, Change{Old: src, New: b[j]})
+			}
+		}
+	}
+
+	match(func(src ImageSource) string { return src.URL })
+	match(descriptorKey)
+
+	for i, src := range a {
+		if !matchedA[i] {
+			changes.Removed = append(changes.Removed, src)
+		}
+	}
+	for j, src := range b {
+		if !matchedB[j] {
+			changes.Added = append(changes.Added, src)
+		}
+	}
+
+	return changes
+}
+
+// descriptorKey returns the serialized descriptors of src.
+func descriptorKey(src ImageSource) string {
+	return string(appendDescriptors(nil, src))
+}
@@ -0,0 +1,73 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Diff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want Changes
+	}{
+		{
+			name: "Identical",
+			a:    "a.png 1x, b.png 2x",
+			b:    "a.png 1x, b.png 2x",
+			want: Changes{},
+		},
+		{
+			name: "Added",
+			a:    "a.png 1x",
+			b:    "a.png 1x, b.png 2x",
+			want: Changes{
+				Added: []ImageSource{{URL: "b.png", Density: fl(2), Offset: 10}},
+			},
+		},
+		{
+			name: "Removed",
+			a:    "a.png 1x, b.png 2x",
+			b:    "a.png 1x",
+			want: Changes{
+				Removed: []ImageSource{{URL: "b.png", Density: fl(2), Offset: 10}},
+			},
+		},
+		{
+			name: "Modified descriptor",
+			a:    "a.png 320w",
+			b:    "a.png 480w",
+			want: Changes{
+				Modified: []Change{{
+					Old: ImageSource{URL: "a.png", Width: i(320)},
+					New: ImageSource{URL: "a.png", Width: i(480)},
+				}},
+			},
+		},
+		{
+			name: "Modified URL",
+			a:    "a.png 320w, b.png 640w",
+			b:    "a.png 320w, c.png 640w",
+			want: Changes{
+				Modified: []Change{{
+					Old: ImageSource{URL: "b.png", Width: i(640), Offset: 12},
+					New: ImageSource{URL: "c.png", Width: i(640), Offset: 12},
+				}},
+			},
+		},
+		{
+			name: "Reordered",
+			a:    "a.png 1x, b.png 2x",
+			b:    "b.png 2x, a.png 1x",
+			want: Changes{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(Parse(tt.a), Parse(tt.b)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Diff() = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
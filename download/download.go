@@ -0,0 +1,273 @@
+// Package download fetches every candidate of a srcset attribute, for tools
+// that archive the whole set rather than the candidate a browser would pick.
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lukasbob/srcset"
+)
+
+// DefaultConcurrency is the number of concurrent requests made when the
+// Concurrency of the Options is zero.
+const DefaultConcurrency = 4
+
+var (
+	// ErrNotAbsolute is reported for candidates whose URL is not an
+	// absolute HTTP or HTTPS URL after resolving it against the base.
+	ErrNotAbsolute = errors.New("download: not an absolute HTTP URL")
+	// ErrTooLarge is reported for bodies larger than MaxBytes.
+	ErrTooLarge = errors.New("download: body too large")
+)
+
+// Options configures Download.
+type Options struct {
+	Client *http.Client // http.DefaultClient if nil
+	// Base is the URL relative candidate URLs are resolved against.
+	Base *url.URL
+
+	// Concurrency caps the number of concurrent requests overall, and
+	// PerHostConcurrency those to a single host. Zero means
+	// DefaultConcurrency overall, and no cap per host.
+	Concurrency        int
+	PerHostConcurrency int
+	// PerHostInterval is the minimum time between the starts of two
+	// requests to the same host. Zero means no rate limit.
+	PerHostInterval time.Duration
+
+	// Dir is the directory the bodies are written to. If it is empty, the
+	// bodies are kept in memory.
+	Dir string
+	// MaxBytes limits the size of each body. Zero means no limit.
+	MaxBytes int64
+}
+
+// Result is the outcome of downloading a single candidate.
+type Result struct {
+	Source      srcset.ImageSource
+	URL         string // the resolved URL
+	StatusCode  int
+	ContentType string
+	Size        int64 // the number of bytes of the body
+	Err         error
+
+	// Body holds the body if Options.Dir is empty, and Path the file it
+	// was written to otherwise.
+	Body []byte
+	Path string
+}
+
+// OK reports whether the candidate was downloaded successfully.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Download fetches every candidate of set with GET requests and returns the
+// results in the order of the candidates. Bodies of responses without a 2xx
+// status are discarded. Files are named after the index of the candidate and
+// the last segment of its URL path, such as "0-hero-320.jpg". Data URLs are
+// not requested; their results only hold the content type.
+func Download(ctx context.Context, set srcset.SourceSet, opts Options) []Result {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var (
+		d = &downloader{
+			opts:  opts,
+			hosts: map[string]*host{},
+		}
+		results = make([]Result, len(set))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i, src := range set {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, src srcset.ImageSource) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = d.download(ctx, i, src)
+		}(i, src)
+	}
+	wg.Wait()
+
+	return results
+}
+
+type downloader struct {
+	opts Options
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+// host limits the requests to a single host.
+type host struct {
+	sem  chan struct{} // nil without a concurrency cap
+	mu   sync.Mutex
+	next time.Time // earliest start of the next request
+}
+
+func (d *downloader) host(name string) *host {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, ok := d.hosts[name]
+	if !ok {
+		h = &host{}
+		if d.opts.PerHostConcurrency > 0 {
+			h.sem = make(chan struct{}, d.opts.PerHostConcurrency)
+		}
+		d.hosts[name] = h
+	}
+	return h
+}
+
+// acquire waits until a request to h may start, and returns a function that
+// releases it again.
+func (h *host) acquire(ctx context.Context, interval time.Duration) (func(), error) {
+	release := func() {}
+	if h.sem != nil {
+		select {
+		case h.sem <- struct{}{}:
+			release = func() { <-h.sem }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if interval <= 0 {
+		return release, nil
+	}
+
+	h.mu.Lock()
+	now := time.Now()
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(interval)
+	h.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return release, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+func (d *downloader) download(ctx context.Context, i int, src srcset.ImageSource) Result {
+	res := Result{Source: src, URL: src.URL}
+
+	if strings.HasPrefix(strings.ToLower(src.URL), "data:") {
+		res.ContentType = src.GuessType()
+		return res
+	}
+	u, err := url.Parse(src.URL)
+	if err == nil && d.opts.Base != nil {
+		u = d.opts.Base.ResolveReference(u)
+	}
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		res.Err = ErrNotAbsolute
+		return res
+	}
+	res.URL = u.String()
+
+	release, err := d.host(u.Host).acquire(ctx, d.opts.PerHostInterval)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	client := d.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+
+	res.StatusCode = resp.StatusCode
+	res.ContentType = resp.Header.Get("Content-Type")
+	if !res.OK() {
+		return res
+	}
+
+	body := io.Reader(resp.Body)
+	if d.opts.MaxBytes > 0 {
+		body = io.LimitReader(body, d.opts.MaxBytes+1)
+	}
+	if d.opts.Dir == "" {
+		res.Body, res.Err = io.ReadAll(body)
+		res.Size = int64(len(res.Body))
+	} else {
+		res.Path = filepath.Join(d.opts.Dir, fileName(i, u))
+		res.Size, res.Err = writeFile(res.Path, body)
+	}
+	if res.Err == nil && d.opts.MaxBytes > 0 && res.Size > d.opts.MaxBytes {
+		res.Err = ErrTooLarge
+		res.Body = nil
+		if res.Path != "" {
+			os.Remove(res.Path)
+			res.Path = ""
+		}
+	}
+	if res.Err != nil {
+		res.Err = fmt.Errorf("download: %s: %w", res.URL, res.Err)
+	}
+	return res
+}
+
+// fileName returns the name of the file for the candidate at index i. The
+// last element of the URL path is decoded, so separators and ".." that
+// would escape the directory on some systems are replaced by underscores.
+func fileName(i int, u *url.URL) string {
+	base := path.Base(u.Path)
+	if base == "." || base == "/" {
+		base = "index"
+	}
+	base = unsafeNameChars.Replace(base)
+	base = strings.ReplaceAll(base, "..", "_")
+	return fmt.Sprintf("%d-%s", i, base)
+}
+
+var unsafeNameChars = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "\x00", "_")
+
+func writeFile(name string, r io.Reader) (int64, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
@@ -0,0 +1,174 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lukasbob/srcset"
+)
+
+func Test_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/img/a.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("PNG data"))
+		case "/img/large.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(make([]byte, 100))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/img/")
+	set := srcset.Parse("a.png 1x, " + srv.URL + "/img/missing.png 2x, large.png 3x, data:image/gif;base64,R0lG 4x, ftp://example.com/a.png 5x")
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		err         error
+	}{
+		{name: "Relative URL", statusCode: 200, contentType: "image/png", body: "PNG data"},
+		{name: "Not found", statusCode: 404, contentType: "text/plain; charset=utf-8"},
+		{name: "Too large", statusCode: 200, contentType: "image/png", err: ErrTooLarge},
+		{name: "Data URL", contentType: "image/gif"},
+		{name: "Not HTTP", err: ErrNotAbsolute},
+	}
+
+	for _, dir := range []string{"", t.TempDir()} {
+		opts := Options{Client: srv.Client(), Base: base, MaxBytes: 50, Dir: dir}
+		results := Download(context.Background(), set, opts)
+		if len(results) != len(tests) {
+			t.Fatalf("Download() returned %d results, want %d", len(results), len(tests))
+		}
+
+		for i, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := results[i]
+				if got.StatusCode != tt.statusCode {
+					t.Errorf("%q. StatusCode = %d, want %d", tt.name, got.StatusCode, tt.statusCode)
+				}
+				if got.ContentType != tt.contentType {
+					t.Errorf("%q. ContentType = %q, want %q", tt.name, got.ContentType, tt.contentType)
+				}
+				if !errors.Is(got.Err, tt.err) {
+					t.Errorf("%q. Err = %v, want %v", tt.name, got.Err, tt.err)
+				}
+
+				body := got.Body
+				if dir != "" && got.Path != "" {
+					body, _ = os.ReadFile(got.Path)
+				}
+				if string(body) != tt.body || (tt.err == nil && got.Size != int64(len(tt.body))) {
+					t.Errorf("%q. body = %q (%d bytes), want %q", tt.name, body, got.Size, tt.body)
+				}
+			})
+		}
+
+		if dir != "" {
+			if want := filepath.Join(dir, "0-a.png"); results[0].Path != want {
+				t.Errorf("Path = %q, want %q", results[0].Path, want)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "2-large.png")); !os.IsNotExist(err) {
+				t.Errorf("file of too large body exists, error = %v", err)
+			}
+		}
+	}
+}
+
+func Test_Download_limits(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		active, peak int
+		starts       []time.Time
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > peak {
+			peak = active
+		}
+		starts = append(starts, time.Now())
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	set := srcset.Parse(srv.URL + "/a.png 1x, " + srv.URL + "/b.png 2x, " + srv.URL + "/c.png 3x, " + srv.URL + "/d.png 4x")
+	const interval = 5 * time.Millisecond
+	results := Download(context.Background(), set, Options{
+		Client:             srv.Client(),
+		Concurrency:        4,
+		PerHostConcurrency: 1,
+		PerHostInterval:    interval,
+	})
+
+	for _, res := range results {
+		if !res.OK() {
+			t.Errorf("%s: Err = %v, StatusCode = %d", res.URL, res.Err, res.StatusCode)
+		}
+	}
+	if peak != 1 {
+		t.Errorf("peak concurrent requests = %d, want 1", peak)
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < interval {
+			t.Errorf("requests %d and %d started %v apart, want at least %v", i-1, i, gap, interval)
+		}
+	}
+}
+
+func Test_Download_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := Download(ctx, srcset.Parse("https://example.com/a.png 1x"), Options{PerHostInterval: time.Second})
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("Err = %v, want %v", results[0].Err, context.Canceled)
+	}
+}
+
+func Test_fileName(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "Plain", path: "/img/a.png", want: "3-a.png"},
+		{name: "Directory", path: "/img/", want: "3-img"},
+		{name: "Root", path: "/", want: "3-index"},
+		{name: "Backslashes", path: "/img/..%5C..%5Cevil.png", want: "3-____evil.png"},
+		{name: "Encoded slashes", path: "/img/..%2F..%2Fevil.png", want: "3-evil.png"},
+		{name: "Dots", path: "/img/..", want: "3-_"},
+		{name: "Drive", path: "/img/C:evil.png", want: "3-C_evil.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse("https://example.com" + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fileName(3, u); got != tt.want {
+				t.Errorf("%q. fileName() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package srcset
+
+import "strconv"
+
+// Mask records which descriptors a candidate reported by EachCandidate has.
+type Mask uint8
+
+// The descriptors of a candidate.
+const (
+	MaskWidth Mask = 1 << iota
+	MaskHeight
+	MaskDensity
+)
+
+// Has reports whether m includes all descriptors of other.
+func (m Mask) Has(other Mask) bool {
+	return m&other == other
+}
+
+// EachCandidate parses input like Parse without options, but calls fn for
+// each valid candidate instead of building a SourceSet, and does not
+// allocate when built with a current Go release. The url passed to fn is a
+// subslice of input and must not be retained or modified; w, h and d are
+// only meaningful if kinds includes the corresponding descriptor. Parsing
+// stops when fn returns false.
+func EachCandidate(input []byte, fn func(url []byte, w, h int64, d float64, kinds Mask) bool) {
+	pos := 0
+	for {
+		for pos < len(input) && (isSpace(rune(input[pos])) || input[pos] == comma) {
+			pos++
+		}
+		if pos >= len(input) {
+			return
+		}
+
+		urlStart := pos
+		for pos < len(input) && !isSpace(rune(input[pos])) {
+			pos++
+		}
+		url := input[urlStart:pos]
+
+		var d descriptorScanner
+		if url[len(url)-1] == comma {
+			for url[len(url)-1] == comma {
+				url = url[:len(url)-1]
+			}
+		} else {
+			pos = d.scan(input, pos)
+		}
+
+		if d.err {
+			continue
+		}
+		if !fn(url, d.w, d.h, d.d, d.kinds) {
+			return
+		}
+	}
+}
+
+// descriptorScanner tokenizes and parses the descriptors of a candidate as
+// parse does, without collecting them.
+type descriptorScanner struct {
+	w, h  int64
+	d     float64
+	kinds Mask
+	err   bool
+}
+
+// scan consumes the descriptors starting at pos, up to and including the
+// comma ending the candidate, and returns the position after them.
+func (s *descriptorScanner) scan(input []byte, pos int) int {
+	for pos < len(input) && isSpace(rune(input[pos])) {
+		pos++
+	}
+
+	var (
+		state     = stateInDescriptor
+		descStart = -1
+	)
+	for ; pos < len(input); pos++ {
+		c := rune(input[pos])
+		switch state {
+		case stateInDescriptor:
+			switch {
+			case isSpace(c):
+				if descStart >= 0 {
+					s.parse(input[descStart:pos])
+					descStart = -1
+					state = stateAfterDescriptor
+				}
+			case c == comma:
+				if descStart >= 0 {
+					s.parse(input[descStart:pos])
+				}
+				return pos + 1
+			case c == leftParens:
+				if descStart < 0 {
+					descStart = pos
+				}
+				state = stateInParens
+			default:
+				if descStart < 0 {
+					descStart = pos
+				}
+			}
+		case stateInParens:
+			if c == rightParens {
+				state = stateInDescriptor
+			}
+		case stateAfterDescriptor:
+			if !isSpace(c) {
+				state = stateInDescriptor
+				pos--
+			}
+		}
+	}
+
+	if state != stateAfterDescriptor && descStart >= 0 {
+		s.parse(input[descStart:])
+	}
+	return pos
+}
+
+// parse parses a single descriptor, recording invalid ones in s.err.
+func (s *descriptorScanner) parse(desc []byte) {
+	last, num := desc[len(desc)-1], desc[:len(desc)-1]
+	switch {
+	case last == 'w' && isDigits(num):
+		s.err = s.err || s.kinds&(MaskWidth|MaskDensity) != 0
+		s.w, s.err = parseDigits(num, s.err)
+		s.kinds |= MaskWidth
+	case last == 'h' && isDigits(num):
+		s.err = s.err || s.kinds&(MaskHeight|MaskDensity) != 0
+		s.h, s.err = parseDigits(num, s.err)
+		s.kinds |= MaskHeight
+	case last == 'x' && isFloat(num):
+		s.err = s.err || s.kinds != 0
+		d, err := strconv.ParseFloat(string(num), 64)
+		s.d, s.err = d, s.err || err != nil || d < 0
+		s.kinds |= MaskDensity
+	default:
+		s.err = true
+	}
+}
+
+func isDigits(b []byte) bool {
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return len(b) > 0
+}
+
+// parseDigits parses a string of digits as a positive integer, setting
+// failed if it is zero or overflows.
+func parseDigits(b []byte, failed bool) (int64, bool) {
+	var n int64
+	for _, c := range b {
+		if n > (1<<63-1-int64(c-'0'))/10 {
+			return 0, true
+		}
+		n = n*10 + int64(c-'0')
+	}
+	return n, failed || n == 0
+}
+
+// isFloat reports whether b is a valid floating-point number as the srcset
+// parser defines it, matching regexFloatingPoint.
+func isFloat(b []byte) bool {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	intDigits := skipDigits(b, &i)
+	if i < len(b) && b[i] == '.' {
+		i++
+		if skipDigits(b, &i) == 0 {
+			return false
+		}
+	} else if intDigits == 0 {
+		return false
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		if skipDigits(b, &i) == 0 {
+			return false
+		}
+	}
+	return i == len(b)
+}
+
+// skipDigits advances *i past the digits in b and returns their number.
+func skipDigits(b []byte, i *int) int {
+	start := *i
+	for *i < len(b) && b[*i] >= '0' && b[*i] <= '9' {
+		*i++
+	}
+	return *i - start
+}
@@ -0,0 +1,73 @@
+package srcset
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_EachCandidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty", input: ""},
+		{name: "Widths", input: "a.png 320w, b.png 640w 480h"},
+		{name: "Densities", input: "a.png, b.png 1.5x,c.png 2e0x"},
+		{name: "Trailing commas", input: "a.png,, b.png,,,c.png 2x"},
+		{name: "Whitespace", input: " \t\na.png \n 320w \r\n, b.png\f640w "},
+		{name: "Invalid descriptors", input: "a.png 0w, b.png -1x, c.png 1w 1x, d.png 2x 200h, e.png 1q, f.png 1w 2w, g.png .x, h.png 1.x"},
+		{name: "Height only", input: "a.png 200h"},
+		{name: "Negative zero", input: "a.png -0x"},
+		{name: "Overflow", input: "a.png 99999999999999999999w, b.png 9223372036854775807w"},
+		{name: "Parentheses", input: "data:,a ( , data:,b 1x, ), data:,c"},
+		{name: "Unterminated parenthesis", input: "a.png (1x, b.png 2x"},
+		{name: "Descriptor before comma", input: "a.png 1x,b.png 2x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SourceSet
+			EachCandidate([]byte(tt.input), func(url []byte, w, h int64, d float64, kinds Mask) bool {
+				got = append(got, Candidate{
+					URL:   string(url),
+					Width: w, HasWidth: kinds.Has(MaskWidth),
+					Height: h, HasHeight: kinds.Has(MaskHeight),
+					Density: d, HasDensity: kinds.Has(MaskDensity),
+				}.ImageSource())
+				return true
+			})
+			if want := Parse(tt.input); !got.Equal(want) {
+				t.Errorf("%q. EachCandidate() = %v, want %v", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func Test_EachCandidate_Stop(t *testing.T) {
+	var urls []string
+	EachCandidate([]byte("a.png 1x, b.png 2x, c.png 3x"), func(url []byte, _, _ int64, _ float64, _ Mask) bool {
+		urls = append(urls, string(url))
+		return len(urls) < 2
+	})
+	if got := strings.Join(urls, " "); got != "a.png b.png" {
+		t.Errorf("EachCandidate() visited %q, want %q", got, "a.png b.png")
+	}
+}
+
+func Test_EachCandidate_Allocs(t *testing.T) {
+	input := []byte("a.png 320w, b.png 640w 480h, c.png 1.5x, data:,a ( , data:,b 1x, ), d.png")
+	allocs := testing.AllocsPerRun(100, func() {
+		EachCandidate(input, func([]byte, int64, int64, float64, Mask) bool { return true })
+	})
+	if allocs != 0 {
+		t.Errorf("EachCandidate() allocations = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkEachCandidate(b *testing.B) {
+	input := []byte(`elva-fairy-320w.jpg 320w, elva-fairy-480w.jpg 480w, elva-fairy-800w.jpg 800w 600h, data:,a ( , data:,b 1x, ), data:,c`)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		EachCandidate(input, func([]byte, int64, int64, float64, Mask) bool { return true })
+	}
+}
@@ -0,0 +1,156 @@
+package srcset
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// ImgElement is the responsive configuration of an img element: the src,
+// srcset and sizes attributes, which only make sense together, and the
+// intrinsic dimensions from the width and height attributes.
+type ImgElement struct {
+	Src    string
+	Srcset SourceSet
+	Sizes  SizeList
+	Width  int64 // zero if absent or invalid
+	Height int64 // zero if absent or invalid
+	Lazy   bool  // loading="lazy"
+}
+
+// ParseImgElement builds an ImgElement from the attributes of an img
+// element, keyed by name. When several attributes configured by
+// WithAttributeNames are present, the first name listed wins. The options
+// are also used to parse the srcset and sizes attributes.
+func ParseImgElement(attrs map[string]string, opts ...Option) ImgElement {
+	var (
+		cfg   = newConfig(opts)
+		names = cfg.attributeNames()
+		lower = lowerKeys(attrs)
+	)
+
+	var img ImgElement
+	if src, ok := lookupAttr(lower, names.Src); ok {
+		img.Src = strings.TrimSpace(src)
+	}
+	if value, ok := lookupAttr(lower, names.Srcset); ok {
+		img.Srcset = parse(value, cfg)
+	}
+	if value, ok := lookupAttr(lower, names.Sizes); ok {
+		img.Sizes = parseSizes(value, cfg)
+	}
+	img.Lazy = strings.EqualFold(strings.TrimSpace(lower["loading"]), "lazy")
+	img.Width = parseDimension(lower["width"])
+	img.Height = parseDimension(lower["height"])
+	return img
+}
+
+// ParseAttrs pulls the srcset, sizes and src attributes out of attrs, as
+// ParseImgElement does, decoding character references in their values. The
+// error is a *ParseError listing the problems of the first of the srcset and
+// sizes attributes that has any; the results hold what could be parsed
+// regardless.
+func ParseAttrs(attrs map[string]string, opts ...Option) (SourceSet, SizeList, string, error) {
+	var (
+		cfg      = newConfig(append(opts[:len(opts):len(opts)], WithEntityDecoding()))
+		names    = cfg.attributeNames()
+		lower    = lowerKeys(attrs)
+		handler  = cfg.warn
+		warnings []Warning
+		err      error
+	)
+	cfg.warn = func(w Warning) {
+		if handler != nil {
+			handler(w)
+		}
+		warnings = append(warnings, w)
+	}
+
+	var (
+		set   SourceSet
+		sizes SizeList
+		src   string
+	)
+	if value, ok := lookupAttr(lower, names.Srcset); ok {
+		set = parse(value, cfg)
+		if len(warnings) > 0 {
+			err = &ParseError{Input: html.UnescapeString(value), Warnings: warnings}
+		}
+	}
+	if value, ok := lookupAttr(lower, names.Sizes); ok {
+		value = html.UnescapeString(value)
+		warnings = nil
+		sizes = parseSizes(value, cfg)
+		if err == nil && len(warnings) > 0 {
+			err = &ParseError{Input: value, Warnings: warnings}
+		}
+	}
+	if value, ok := lookupAttr(lower, names.Src); ok {
+		src = strings.TrimSpace(html.UnescapeString(value))
+	}
+	return set, sizes, src, err
+}
+
+// lowerKeys returns a copy of attrs with lowercase keys.
+func lowerKeys(attrs map[string]string) map[string]string {
+	lower := make(map[string]string, len(attrs))
+	for name, value := range attrs {
+		lower[strings.ToLower(name)] = value
+	}
+	return lower
+}
+
+// lookupAttr returns the value of the first of names in lower, which has
+// lowercase keys.
+func lookupAttr(lower map[string]string, names []string) (string, bool) {
+	for _, name := range names {
+		if value, ok := lower[strings.ToLower(name)]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// parseDimension parses the value of a width or height attribute as a
+// non-negative integer, returning zero if it is invalid.
+func parseDimension(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Candidates returns the candidates a browser chooses from: the srcset,
+// plus the src as a 1x candidate unless the srcset has a width or 1x
+// candidate.
+func (img ImgElement) Candidates() SourceSet {
+	set := img.Srcset
+	if img.Src == "" || len(set.Widths()) > 0 {
+		return set
+	}
+	for _, c := range set.Candidates() {
+		if c.density() == 1 {
+			return set
+		}
+	}
+	return append(set.Clone(), ImageSource{URL: img.Src})
+}
+
+// Select returns the URL a browser would load for a viewport of the given
+// width in CSS pixels and device pixel ratio, or false if there is none.
+func (img ImgElement) Select(viewportWidth, dpr float64) (string, bool) {
+	return img.SelectWithLayout(viewportWidth, dpr, 0)
+}
+
+// SelectWithLayout is like Select, but for a lazy-loaded image with
+// sizes="auto", layoutWidth is used as the source size, if it is positive.
+func (img ImgElement) SelectWithLayout(viewportWidth, dpr, layoutWidth float64) (string, bool) {
+	candidates := img.Candidates()
+	if img.Lazy && img.Sizes.Auto() && layoutWidth > 0 {
+		src, ok := candidates.BestForLayout(layoutWidth, dpr)
+		return src.URL, ok
+	}
+	src, ok := candidates.BestForViewport(viewportWidth, dpr, img.Sizes)
+	return src.URL, ok
+}
@@ -0,0 +1,214 @@
+package srcset
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_ParseImgElement(t *testing.T) {
+	img := ParseImgElement(map[string]string{
+		"SRC":    " fallback.png ",
+		"srcset": "a.png 320w, b.png 640w",
+		"sizes":  "(min-width: 800px) 50vw, 100vw",
+		"width":  "640",
+		"height": "auto",
+	})
+
+	if img.Src != "fallback.png" {
+		t.Errorf("Src = %q, want %q", img.Src, "fallback.png")
+	}
+	if got := img.Srcset.String(); got != "a.png 320w, b.png 640w" {
+		t.Errorf("Srcset = %q, want %q", got, "a.png 320w, b.png 640w")
+	}
+	if got := img.Sizes.String(); got != "(min-width: 800px) 50vw, 100vw" {
+		t.Errorf("Sizes = %q, want %q", got, "(min-width: 800px) 50vw, 100vw")
+	}
+	if img.Width != 640 || img.Height != 0 {
+		t.Errorf("size = %dx%d, want 640x0", img.Width, img.Height)
+	}
+}
+
+func Test_ParseImgElement_lazy(t *testing.T) {
+	attrs := map[string]string{
+		"src":         "placeholder.gif",
+		"data-src":    "a.png",
+		"data-srcset": "a.png 1x, b.png 2x",
+	}
+
+	img := ParseImgElement(attrs, WithAttributeNames(AttributeNames{
+		Srcset: []string{"data-srcset", "srcset"},
+		Src:    []string{"data-src", "src"},
+	}))
+	if img.Src != "a.png" || len(img.Srcset) != 2 {
+		t.Errorf("ParseImgElement() = %+v, want data attributes", img)
+	}
+}
+
+func Test_ImgElement_Select(t *testing.T) {
+	tests := []struct {
+		name   string
+		attrs  map[string]string
+		vw     float64
+		dpr    float64
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "Width candidates with sizes",
+			attrs:  map[string]string{"src": "c.png", "srcset": "a.png 320w, b.png 640w", "sizes": "(min-width: 800px) 50vw, 100vw"},
+			vw:     1000,
+			dpr:    1,
+			want:   "b.png",
+			wantOk: true,
+		},
+		{
+			name:   "Src as 1x candidate",
+			attrs:  map[string]string{"src": "a.png", "srcset": "b.png 2x"},
+			vw:     1000,
+			dpr:    1,
+			want:   "a.png",
+			wantOk: true,
+		},
+		{
+			name:   "Src ignored with 1x candidate",
+			attrs:  map[string]string{"src": "a.png", "srcset": "b.png, c.png 2x"},
+			vw:     1000,
+			dpr:    1,
+			want:   "b.png",
+			wantOk: true,
+		},
+		{
+			name:   "Src only",
+			attrs:  map[string]string{"src": "a.png"},
+			vw:     1000,
+			dpr:    3,
+			want:   "a.png",
+			wantOk: true,
+		},
+		{
+			name:  "Nothing",
+			attrs: map[string]string{"alt": "a"},
+			vw:    1000,
+			dpr:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseImgElement(tt.attrs).Select(tt.vw, tt.dpr)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("%q. Select() = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_ImgElement_SelectWithLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		attrs  map[string]string
+		layout float64
+		want   string
+	}{
+		{
+			name:   "Lazy with auto",
+			attrs:  map[string]string{"srcset": "a.png 320w, b.png 640w, c.png 1280w", "sizes": "auto, 100vw", "loading": "LAZY"},
+			layout: 300,
+			want:   "a.png",
+		},
+		{
+			name:   "Lazy with auto without layout width",
+			attrs:  map[string]string{"srcset": "a.png 320w, b.png 640w, c.png 1280w", "sizes": "auto, 100vw", "loading": "lazy"},
+			layout: 0,
+			want:   "c.png",
+		},
+		{
+			name:   "Eager with auto",
+			attrs:  map[string]string{"srcset": "a.png 320w, b.png 640w, c.png 1280w", "sizes": "auto, 50vw"},
+			layout: 300,
+			want:   "b.png",
+		},
+		{
+			name:   "Lazy without auto",
+			attrs:  map[string]string{"srcset": "a.png 320w, b.png 640w, c.png 1280w", "sizes": "50vw", "loading": "lazy"},
+			layout: 300,
+			want:   "b.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := ParseImgElement(tt.attrs).SelectWithLayout(1000, 1, tt.layout)
+			if got != tt.want {
+				t.Errorf("%q. SelectWithLayout() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseAttrs(t *testing.T) {
+	tests := []struct {
+		name      string
+		attrs     map[string]string
+		want      string
+		wantSizes string
+		wantSrc   string
+		wantErr   error
+		wantInput string
+	}{
+		{
+			name:      "Entities",
+			attrs:     map[string]string{"SRCSET": "a.png?w=1&amp;h=2 1x&#44; b.png 2x", "sizes": "(min-width: 600px)&#x20;50vw", "src": "a.png?w=1&amp;h=2"},
+			want:      "a.png?w=1&h=2 1x, b.png 2x",
+			wantSizes: "(min-width: 600px) 50vw",
+			wantSrc:   "a.png?w=1&h=2",
+		},
+		{
+			name:      "Invalid srcset",
+			attrs:     map[string]string{"srcset": "a.png 1q, b.png 2x", "sizes": "50%"},
+			want:      "b.png 2x",
+			wantErr:   ErrInvalidDescriptor,
+			wantInput: "a.png 1q, b.png 2x",
+		},
+		{
+			name:      "Invalid sizes",
+			attrs:     map[string]string{"srcset": "a.png 320w", "sizes": "50%, 100vw"},
+			want:      "a.png 320w",
+			wantSizes: "100vw",
+			wantErr:   ErrInvalidSize,
+			wantInput: "50%, 100vw",
+		},
+		{
+			name:  "Empty",
+			attrs: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, sizes, src, err := ParseAttrs(tt.attrs)
+			if set.String() != tt.want || sizes.String() != tt.wantSizes || src != tt.wantSrc {
+				t.Errorf("%q. ParseAttrs() = %q, %q, %q, want %q, %q, %q", tt.name, set, sizes, src, tt.want, tt.wantSizes, tt.wantSrc)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("%q. ParseAttrs() error = %v, want nil", tt.name, err)
+				}
+				return
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) || !errors.Is(err, tt.wantErr) || pe.Input != tt.wantInput {
+				t.Errorf("%q. ParseAttrs() error = %#v, want %v in %q", tt.name, err, tt.wantErr, tt.wantInput)
+			}
+		})
+	}
+}
+
+func Test_ParseAttrs_options(t *testing.T) {
+	// The options of the caller are not modified, even with spare capacity.
+	opts := []Option{WithMaxCandidates(10), func(*config) {}}
+	ParseAttrs(map[string]string{"srcset": "a.png 1x"}, opts[:1]...)
+	if newConfig(opts).decodeEntities {
+		t.Error("ParseAttrs() appended to the options of the caller")
+	}
+}
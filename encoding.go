@@ -0,0 +1,30 @@
+package srcset
+
+// MarshalText implements encoding.TextMarshaler. The SourceSet is encoded as
+// the value of a srcset attribute.
+func (s SourceSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The text is parsed with
+// ParseStrict, so invalid input results in an error.
+func (s *SourceSet) UnmarshalText(text []byte) error {
+	set, err := ParseStrict(string(text))
+	if err != nil {
+		return err
+	}
+	*s = set
+	return nil
+}
+
+// Set implements flag.Value, so that a SourceSet can be a command-line flag
+// through flag.Var. The value is parsed as UnmarshalText does.
+func (s *SourceSet) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// Type names the type of the flag value in usage messages, as required by
+// the Value interface of github.com/spf13/pflag.
+func (s *SourceSet) Type() string {
+	return "srcset"
+}
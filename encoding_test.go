@@ -0,0 +1,94 @@
+package srcset
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func Test_textEncoding(t *testing.T) {
+	type config struct {
+		Images SourceSet `json:"images"`
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    SourceSet
+		wantErr bool
+	}{
+		{
+			name:  "Valid",
+			input: `{"images":"a.png 1x, b.png 2x"}`,
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 10},
+			},
+		},
+		{
+			name:    "Invalid",
+			input:   `{"images":"a.png 1x 2x"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c config
+			err := json.Unmarshal([]byte(tt.input), &c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. Unmarshal() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(c.Images, tt.want) {
+				t.Errorf("%q. Unmarshal() = %v, want %v", tt.name, c.Images, tt.want)
+			}
+			out, err := json.Marshal(c)
+			if err != nil {
+				t.Fatalf("%q. Marshal() error = %v", tt.name, err)
+			}
+			if string(out) != tt.input {
+				t.Errorf("%q. Marshal() = %s, want %s", tt.name, out, tt.input)
+			}
+		})
+	}
+}
+
+func Test_flagValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "Not set", args: nil, want: "a.png"},
+		{name: "Valid", args: []string{"-srcset", "a.png 1x, b.png 2x"}, want: "a.png 1x, b.png 2x"},
+		{name: "Invalid", args: []string{"-srcset", "a.png 1x 2x"}, want: "a.png", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			set := Parse("a.png")
+			fs.Var(&set, "srcset", "candidates")
+
+			err := fs.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%q. Parse() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got := set.String(); got != tt.want {
+				t.Errorf("%q. flag value = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	var set SourceSet
+	if got := set.Type(); got != "srcset" {
+		t.Errorf("Type() = %q, want %q", got, "srcset")
+	}
+}
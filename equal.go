@@ -0,0 +1,111 @@
+package srcset
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether src and other have the same URL and descriptors,
+// including custom and raw descriptors. The Offset of the candidates is
+// ignored.
+func (src ImageSource) Equal(other ImageSource) bool {
+	if len(src.Extensions) != len(other.Extensions) || len(src.RawDescriptors) != len(other.RawDescriptors) {
+		return false
+	}
+	for i, desc := range src.RawDescriptors {
+		if other.RawDescriptors[i] != desc {
+			return false
+		}
+	}
+	for key, value := range src.Extensions {
+		if v, ok := other.Extensions[key]; !ok || v != value {
+			return false
+		}
+	}
+	return src.Candidate().Equal(other.Candidate())
+}
+
+// Equal reports whether c and other have the same URL and descriptors. The
+// Offset of the candidates, and descriptor values without their Has field
+// set, are ignored.
+func (c Candidate) Equal(other Candidate) bool {
+	return c.normalize() == other.normalize()
+}
+
+func (c Candidate) normalize() Candidate {
+	c.Offset = 0
+	if !c.HasWidth {
+		c.Width = 0
+	}
+	if !c.HasHeight {
+		c.Height = 0
+	}
+	if !c.HasDensity {
+		c.Density = 0
+	}
+	return c
+}
+
+// Equal reports whether s and other contain equal candidates in the same
+// order. See ImageSource.Equal.
+func (s SourceSet) Equal(other SourceSet) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if !s[i].Equal(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a 64-bit FNV-1a hash of the serialized SourceSet. Sets that
+// are Equal have the same hash.
+func (s SourceSet) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s.String()))
+	return h.Sum64()
+}
+
+// SemanticEqual reports whether a and b offer browsers the same choice of
+// images, regardless of order and formatting: duplicate descriptors are
+// removed as Normalize does, candidates without descriptors count as 1x, and
+// URLs are compared after canonicalizing them as Canonicalize does with
+// HostASCII.
+func SemanticEqual(a, b SourceSet) bool {
+	return a.CanonicalKey() == b.CanonicalKey()
+}
+
+// CanonicalKey returns a compact string that identifies s up to the
+// differences SemanticEqual ignores, for use as a cache or deduplication key.
+// The key is itself a valid srcset attribute value: the normalized
+// candidates, with canonical URLs percent-encoded as AppendEncoded does and
+// explicit descriptors with numbers in their shortest form, sorted and joined
+// by commas without whitespace.
+//
+// The format of the key is stable: future versions of this package produce
+// the same key for the same set, except where a change in URL
+// canonicalization fixes a bug. Such changes are called out in the release
+// notes.
+func (s SourceSet) CanonicalKey() string {
+	return strings.Join(s.semanticKeys(), ",")
+}
+
+// semanticKeys returns the sorted encoded serializations of the normalized
+// candidates of s, with canonical URLs and explicit densities.
+func (s SourceSet) semanticKeys() []string {
+	normalized := s.Normalize()
+	keys := make([]string, len(normalized))
+	for i, src := range normalized {
+		src.URL = canonicalURL(src.URL, HostASCII)
+		if src.Width == nil && src.Density == nil {
+			d := 1.0
+			src.Density = &d
+		}
+		keys[i] = string(appendDescriptors(appendEncodedURL(nil, src.URL), src))
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package srcset
+
+import "testing"
+
+func Test_Equal(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b SourceSet
+		want bool
+	}{
+		{
+			name: "Same input",
+			a:    Parse("a.png 1x, b.png 2x"),
+			b:    Parse("a.png 1x, b.png 2x"),
+			want: true,
+		},
+		{
+			name: "Different formatting",
+			a:    Parse("a.png 1x, b.png 2x"),
+			b:    Parse("  a.png   1x,\n b.png 2.0x"),
+			want: true,
+		},
+		{
+			name: "Different order",
+			a:    Parse("a.png 1x, b.png 2x"),
+			b:    Parse("b.png 2x, a.png 1x"),
+			want: false,
+		},
+		{
+			name: "Different descriptor",
+			a:    Parse("a.png 1x"),
+			b:    Parse("a.png 100w"),
+			want: false,
+		},
+		{
+			name: "Missing descriptor",
+			a:    Parse("a.png 1x"),
+			b:    Parse("a.png"),
+			want: false,
+		},
+		{
+			name: "Different length",
+			a:    Parse("a.png 1x"),
+			b:    Parse("a.png 1x, b.png 2x"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("%q. Equal() = %v, want %v", tt.name, got, tt.want)
+			}
+			if got := tt.a.Hash() == tt.b.Hash(); got != tt.want {
+				t.Errorf("%q. Hash() equality = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SemanticEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "Identical", a: "a.png 1x, b.png 2x", b: "a.png 1x, b.png 2x", want: true},
+		{name: "Order", a: "a.png 1x, b.png 2x", b: "b.png 2x, a.png 1x", want: true},
+		{name: "Formatting", a: "a.png 320w,b.png 640w", b: " a.png\t320w ,\nb.png 640w ", want: true},
+		{name: "Default density", a: "a.png, b.png 2x", b: "a.png 1.0x, b.png 2x", want: true},
+		{name: "Duplicate descriptor", a: "a.png, b.png 1x, c.png 2x", b: "a.png, c.png 2x", want: true},
+		{name: "Canonical URL", a: "HTTPS://Example.com:443/a/../a.png 2x", b: "https://example.com/a.png 2x", want: true},
+		{name: "Internationalized host", a: "https://bücher.de/a.png", b: "https://xn--bcher-kva.de/a.png", want: true},
+		{name: "Different density", a: "a.png 1x", b: "a.png 2x", want: false},
+		{name: "Different URL", a: "a.png 1x", b: "b.png 1x", want: false},
+		{name: "Different length", a: "a.png 1x", b: "a.png 1x, b.png 2x", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SemanticEqual(Parse(tt.a), Parse(tt.b)); got != tt.want {
+				t.Errorf("%q. SemanticEqual() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CanonicalKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Empty", input: "", want: ""},
+		{name: "Sorted", input: "b.png 2x, a.png", want: "a.png 1x,b.png 2x"},
+		{name: "Numbers", input: "a.png 2.50x, b.png 1e0x", want: "a.png 2.5x,b.png 1x"},
+		{name: "Widths", input: "https://EXAMPLE.com/b.png 640w 480h, /a.png 320w", want: "/a.png 320w,https://example.com/b.png 640w 480h"},
+		{name: "Duplicates", input: "a.png, b.png 1x", want: "a.png 1x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).CanonicalKey(); got != tt.want {
+				t.Errorf("%q. CanonicalKey() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	set := SourceSet{{URL: "a,.png"}, {URL: "b c.png,", Density: fl(2)}}
+	if got, want := set.CanonicalKey(), "a,.png 1x,b%20c.png%2C 2x"; got != want {
+		t.Errorf("CanonicalKey() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package srcset
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors classifying the problems found while parsing or validating. They
+// are reported in the Err fields of Warning and Violation, and can be
+// matched against a *ParseError with errors.Is.
+var (
+	ErrInvalidDescriptor   = errors.New("srcset: invalid descriptor")
+	ErrDuplicateDescriptor = errors.New("srcset: duplicate descriptor")
+	ErrMixedDescriptors    = errors.New("srcset: mixed descriptors")
+	ErrZeroWidth           = errors.New("srcset: width not greater than zero")
+	ErrInvalidHeight       = errors.New("srcset: height not greater than zero")
+	ErrZeroDensity         = errors.New("srcset: density not greater than zero")
+	ErrNegativeDensity     = errors.New("srcset: negative density")
+	ErrEmptyURL            = errors.New("srcset: empty URL")
+	ErrInvalidURL          = errors.New("srcset: invalid URL")
+	ErrDisallowedScheme    = errors.New("srcset: disallowed URL scheme")
+	ErrProtocolRelative    = errors.New("srcset: protocol-relative URL")
+	ErrCandidateLimit      = errors.New("srcset: candidate limit exceeded")
+	ErrEmptySet            = errors.New("srcset: no candidates")
+	ErrSyntax              = errors.New("srcset: syntax error")
+	ErrInvalidSize         = errors.New("srcset: invalid source size")
+	ErrSizesMismatch       = errors.New("srcset: sizes attribute does not match descriptors")
+	ErrUnsupported         = errors.New("srcset: feature not supported by the target syntax")
+)
+
+// CandidateError is a problem with a single candidate, or with a part of the
+// input between candidates.
+type CandidateError struct {
+	Offset int    // byte offset of Text in the input
+	Text   string // the offending part of the input
+	Err    error  // one of the Err variables
+}
+
+func (e *CandidateError) Error() string {
+	return fmt.Sprintf("%v at offset %d (%q)", e.Err, e.Offset, e.Text)
+}
+
+func (e *CandidateError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns a *CandidateError for each warning.
+func (e *ParseError) Errors() []error {
+	errs := make([]error, len(e.Warnings))
+	for i, w := range e.Warnings {
+		errs[i] = &CandidateError{Offset: w.Offset, Text: w.Text, Err: w.Err}
+	}
+	return errs
+}
+
+// Is reports whether any warning matches target, such that
+// errors.Is(err, ErrZeroWidth) reports whether any candidate had a zero
+// width.
+func (e *ParseError) Is(target error) bool {
+	for _, err := range e.Errors() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first warning that matches target, such that errors.As with
+// a **CandidateError yields the first problem with its context.
+func (e *ParseError) As(target interface{}) bool {
+	for _, err := range e.Errors() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package srcset
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  error
+	}{
+		{name: "Invalid descriptor", input: "a.png 1q", want: ErrInvalidDescriptor},
+		{name: "Duplicate width", input: "a.png 1w 2w", want: ErrDuplicateDescriptor},
+		{name: "Duplicate density", input: "a.png 1x 2x", want: ErrDuplicateDescriptor},
+		{name: "Width and density", input: "a.png 1w 2x", want: ErrMixedDescriptors},
+		{name: "Density and height", input: "a.png 1x 2h", want: ErrMixedDescriptors},
+		{name: "Lone height", input: "a.png 2h", opts: []Option{WithSpecProfile(Living)}, want: ErrMixedDescriptors},
+		{name: "Zero width", input: "a.png 0w", want: ErrZeroWidth},
+		{name: "Zero height", input: "a.png 10w 0h", want: ErrInvalidHeight},
+		{name: "Negative density", input: "a.png -1x", want: ErrNegativeDensity},
+		{name: "Disallowed scheme", input: "javascript:a 1x", opts: []Option{WithAllowedSchemes()}, want: ErrDisallowedScheme},
+		{name: "Candidate limit", input: "a.png 1x, b.png 2x", opts: []Option{WithMaxCandidates(1)}, want: ErrCandidateLimit},
+		{name: "Syntax", input: "a.png 1x,, b.png 2x", want: ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStrict(tt.input, tt.opts...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%q. ParseStrict() error = %v, want %v", tt.name, err, tt.want)
+			}
+
+			var cerr *CandidateError
+			if !errors.As(err, &cerr) || cerr.Err != tt.want {
+				t.Errorf("%q. errors.As() = %v, want a *CandidateError for %v", tt.name, cerr, tt.want)
+			}
+		})
+	}
+}
+
+func Test_errors_notMatching(t *testing.T) {
+	_, err := ParseStrict("a.png 1q")
+	if errors.Is(err, ErrZeroWidth) {
+		t.Errorf("errors.Is(%v, ErrZeroWidth) = true, want false", err)
+	}
+}
+
+func Test_CandidateError(t *testing.T) {
+	err := &CandidateError{Offset: 3, Text: "a.png 0w", Err: ErrZeroWidth}
+	if got, want := err.Error(), `srcset: width not greater than zero at offset 3 ("a.png 0w")`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, ErrZeroWidth) {
+		t.Error("errors.Is() = false, want true")
+	}
+}
+
+func Test_Violation_Err(t *testing.T) {
+	violations := Parse("a.png 1x, b.png 1x, //c.png 2x").Validate()
+	if len(violations) != 1 || !errors.Is(violations[0].Err, ErrDuplicateDescriptor) {
+		t.Errorf("Validate() = %v, want a duplicate descriptor", violations)
+	}
+}
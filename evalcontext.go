@@ -0,0 +1,31 @@
+package srcset
+
+// EvalContext holds what is needed to resolve relative CSS lengths to
+// pixels. Zero fields take defaults: the font sizes are 16px, and the
+// viewport height equals its width.
+type EvalContext struct {
+	ViewportWidth  float64 // in CSS pixels, resolves vw and the width feature
+	ViewportHeight float64 // in CSS pixels, resolves vh
+	FontSize       float64 // in CSS pixels, resolves em
+	RootFontSize   float64 // in CSS pixels, resolves rem
+}
+
+// Viewport returns an EvalContext for a viewport of the given width in CSS
+// pixels, with defaults for everything else.
+func Viewport(width float64) EvalContext {
+	return EvalContext{ViewportWidth: width}
+}
+
+// withDefaults returns ctx with zero fields set to their defaults.
+func (ctx EvalContext) withDefaults() EvalContext {
+	if ctx.ViewportHeight == 0 {
+		ctx.ViewportHeight = ctx.ViewportWidth
+	}
+	if ctx.FontSize == 0 {
+		ctx.FontSize = defaultFontSize
+	}
+	if ctx.RootFontSize == 0 {
+		ctx.RootFontSize = defaultFontSize
+	}
+	return ctx
+}
@@ -0,0 +1,40 @@
+package srcset
+
+import "testing"
+
+func Test_EvaluateContext(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes string
+		ctx   EvalContext
+		want  float64
+	}{
+		{name: "Defaults", sizes: "10em, 100vw", ctx: Viewport(800), want: 160},
+		{name: "Font size", sizes: "10em", ctx: EvalContext{ViewportWidth: 800, FontSize: 20}, want: 200},
+		{name: "Root font size", sizes: "10rem", ctx: EvalContext{ViewportWidth: 800, FontSize: 20, RootFontSize: 10}, want: 100},
+		{name: "Viewport height", sizes: "50vh", ctx: EvalContext{ViewportWidth: 800, ViewportHeight: 600}, want: 300},
+		{name: "Viewport height default", sizes: "50vh", ctx: Viewport(800), want: 400},
+		{name: "Vmin", sizes: "50vmin", ctx: EvalContext{ViewportWidth: 800, ViewportHeight: 600}, want: 300},
+		{name: "Vmax", sizes: "50vmax", ctx: EvalContext{ViewportWidth: 800, ViewportHeight: 600}, want: 400},
+		{name: "Condition in ems", sizes: "(min-width: 40em) 50vw, 100vw", ctx: EvalContext{ViewportWidth: 800, FontSize: 25}, want: 800},
+		{name: "Calc", sizes: "calc(100vh - 2rem)", ctx: EvalContext{ViewportWidth: 800, ViewportHeight: 600, RootFontSize: 20}, want: 560},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSizes(tt.sizes).EvaluateContext(tt.ctx); got != tt.want {
+				t.Errorf("%q. EvaluateContext() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_BestForContext(t *testing.T) {
+	set := Parse("a.png 300w, b.png 600w")
+	sizes := ParseSizes("50vh")
+
+	got, ok := set.BestForContext(EvalContext{ViewportWidth: 1200, ViewportHeight: 600}, 1, sizes)
+	if !ok || got.URL != "a.png" {
+		t.Errorf("BestForContext() = %v, %v, want a.png", got, ok)
+	}
+}
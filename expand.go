@@ -0,0 +1,83 @@
+package srcset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Placeholders substituted by ExpandTemplate.
+const (
+	WidthPlaceholder = "{width}"
+	DPRPlaceholder   = "{dpr}"
+)
+
+// ExpandTemplate builds a set from a URL template, such as
+// "/img/hero-{width}.jpg" or "/img/hero@{dpr}x.jpg":
+//
+//   - Given widths, it returns a width candidate for each of them, with
+//     {width} replaced by the width.
+//   - Given densities, it returns a density candidate for each of them, with
+//     {dpr} replaced by the density.
+//   - Given a single width and densities, it returns a density candidate for
+//     each density, with {width} replaced by the width multiplied by the
+//     density, and {dpr} replaced by the density.
+//
+// The template must contain the placeholders it needs, so that the
+// candidates do not all share the same URL.
+func ExpandTemplate(tmpl string, widths []int64, densities []float64) (SourceSet, error) {
+	var (
+		hasWidth = strings.Contains(tmpl, WidthPlaceholder)
+		hasDPR   = strings.Contains(tmpl, DPRPlaceholder)
+	)
+
+	switch {
+	case len(widths) == 0 && len(densities) == 0:
+		return nil, errors.New("srcset: no widths or densities")
+	case len(densities) == 0:
+		if !hasWidth {
+			return nil, fmt.Errorf("srcset: template has no %s placeholder", WidthPlaceholder)
+		}
+		if hasDPR {
+			return nil, fmt.Errorf("srcset: template has a %s placeholder, but no densities are given", DPRPlaceholder)
+		}
+	case len(widths) > 1:
+		return nil, errors.New("srcset: cannot expand several widths with densities")
+	case len(widths) == 1 && !hasWidth && !hasDPR:
+		return nil, fmt.Errorf("srcset: template has no %s or %s placeholder", WidthPlaceholder, DPRPlaceholder)
+	case len(widths) == 0 && hasWidth:
+		return nil, fmt.Errorf("srcset: template has a %s placeholder, but no width is given", WidthPlaceholder)
+	case len(widths) == 0 && !hasDPR:
+		return nil, fmt.Errorf("srcset: template has no %s placeholder", DPRPlaceholder)
+	}
+
+	var set SourceSet
+	if len(densities) == 0 {
+		set = make(SourceSet, len(widths))
+		for i, w := range widths {
+			if w <= 0 {
+				return nil, fmt.Errorf("srcset: width %d is not greater than zero", w)
+			}
+			width := w
+			set[i] = ImageSource{URL: strings.ReplaceAll(tmpl, WidthPlaceholder, strconv.FormatInt(w, 10)), Width: &width}
+		}
+		return set, nil
+	}
+
+	set = make(SourceSet, len(densities))
+	for i, d := range densities {
+		if d <= 0 {
+			return nil, fmt.Errorf("srcset: density %s is not greater than zero", formatFloat(d))
+		}
+		url := strings.ReplaceAll(tmpl, DPRPlaceholder, formatFloat(d))
+		if len(widths) == 1 {
+			w := int64(math.Round(float64(widths[0]) * d))
+			url = strings.ReplaceAll(url, WidthPlaceholder, strconv.FormatInt(w, 10))
+		}
+		density := d
+		set[i] = ImageSource{URL: url, Density: &density}
+	}
+	return set, nil
+}
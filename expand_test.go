@@ -0,0 +1,101 @@
+package srcset
+
+import "testing"
+
+func Test_ExpandTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tmpl      string
+		widths    []int64
+		densities []float64
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:   "Widths",
+			tmpl:   "/img/hero-{width}.jpg?w={width}",
+			widths: []int64{320, 640},
+			want:   "/img/hero-320.jpg?w=320 320w, /img/hero-640.jpg?w=640 640w",
+		},
+		{
+			name:      "Densities",
+			tmpl:      "/img/hero@{dpr}x.jpg",
+			densities: []float64{1, 1.5, 2},
+			want:      "/img/hero@1x.jpg 1x, /img/hero@1.5x.jpg 1.5x, /img/hero@2x.jpg 2x",
+		},
+		{
+			name:      "Width and densities",
+			tmpl:      "/img/hero.jpg?w={width}",
+			widths:    []int64{400},
+			densities: []float64{1, 1.5, 2},
+			want:      "/img/hero.jpg?w=400 1x, /img/hero.jpg?w=600 1.5x, /img/hero.jpg?w=800 2x",
+		},
+		{
+			name:    "Widths without placeholder",
+			tmpl:    "/img/hero.jpg",
+			widths:  []int64{320, 640},
+			wantErr: true,
+		},
+		{
+			name:    "Widths with density placeholder",
+			tmpl:    "/img/hero-{width}@{dpr}x.jpg",
+			widths:  []int64{320},
+			wantErr: true,
+		},
+		{
+			name:      "Densities without placeholder",
+			tmpl:      "/img/hero-{width}.jpg",
+			densities: []float64{1, 2},
+			wantErr:   true,
+		},
+		{
+			name:      "Densities with width placeholder",
+			tmpl:      "/img/hero-{width}@{dpr}x.jpg",
+			densities: []float64{1, 2},
+			wantErr:   true,
+		},
+		{
+			name:      "Width and densities without placeholder",
+			tmpl:      "/img/hero.jpg",
+			widths:    []int64{400},
+			densities: []float64{1, 2},
+			wantErr:   true,
+		},
+		{
+			name:      "Several widths and densities",
+			tmpl:      "/img/hero-{width}@{dpr}x.jpg",
+			widths:    []int64{320, 640},
+			densities: []float64{1, 2},
+			wantErr:   true,
+		},
+		{
+			name:    "Nothing to expand",
+			tmpl:    "/img/hero-{width}.jpg",
+			wantErr: true,
+		},
+		{
+			name:    "Zero width",
+			tmpl:    "/img/hero-{width}.jpg",
+			widths:  []int64{0},
+			wantErr: true,
+		},
+		{
+			name:      "Negative density",
+			tmpl:      "/img/hero@{dpr}x.jpg",
+			densities: []float64{-1},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandTemplate(tt.tmpl, tt.widths, tt.densities)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. ExpandTemplate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if s := got.String(); s != tt.want {
+				t.Errorf("%q. ExpandTemplate() = %q, want %q", tt.name, s, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package srcset
+
+import (
+	xhtml "golang.org/x/net/html"
+)
+
+// Declaration is a responsive image declared by an img, source or link
+// element.
+type Declaration struct {
+	Node *xhtml.Node
+	ImgElement
+
+	Type  string // the type attribute of source elements
+	Media string // the media attribute of source elements
+}
+
+// ExtractFromNode returns the responsive images declared by n and its
+// descendants, in document order: the img, source and link elements that
+// have a srcset attribute, and img elements that have a src attribute. The
+// options are used as by ParseImgElement, and configure the attributes.
+func ExtractFromNode(n *xhtml.Node, opts ...Option) []Declaration {
+	var (
+		cfg   = newConfig(opts)
+		names = cfg.attributeNames()
+		decls []Declaration
+	)
+
+	var walk func(n *xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		if n.Type == xhtml.ElementNode {
+			switch n.Data {
+			case "img", "source", "link":
+				attrs := make(map[string]string, len(n.Attr))
+				for _, attr := range n.Attr {
+					if _, ok := attrs[attr.Key]; !ok && attr.Namespace == "" {
+						attrs[attr.Key] = attr.Val
+					}
+				}
+				lower := lowerKeys(attrs)
+				_, hasSrcset := lookupAttr(lower, names.Srcset)
+				_, hasSrc := lookupAttr(lower, names.Src)
+				if hasSrcset || n.Data == "img" && hasSrc {
+					decls = append(decls, Declaration{
+						Node:       n,
+						ImgElement: ParseImgElement(attrs, opts...),
+						Type:       lower["type"],
+						Media:      lower["media"],
+					})
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return decls
+}
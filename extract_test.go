@@ -0,0 +1,67 @@
+package srcset
+
+import (
+	"strings"
+	"testing"
+
+	xhtml "golang.org/x/net/html"
+)
+
+func Test_ExtractFromNode(t *testing.T) {
+	doc, err := xhtml.Parse(strings.NewReader(`<!DOCTYPE html>
+<link rel="preload" as="image" imagesrcset="hero.avif 1x" imagesizes="100vw">
+<main>
+  <picture>
+    <source type="image/avif" media="(min-width: 800px)" srcset="a.avif 800w" sizes="50vw">
+    <img src="a.jpg" srcset="a.jpg 800w" sizes="50vw" alt="">
+  </picture>
+  <img alt="no sources">
+  <img data-src="lazy.jpg">
+</main>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var main *xhtml.Node
+	var find func(n *xhtml.Node)
+	find = func(n *xhtml.Node) {
+		if n.Type == xhtml.ElementNode && n.Data == "main" {
+			main = n
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(doc)
+
+	tests := []struct {
+		name string
+		node *xhtml.Node
+		opts []Option
+		want []string
+	}{
+		{name: "Document", node: doc, want: []string{"link hero.avif 1x", "source a.avif 800w", "img a.jpg 800w"}},
+		{name: "Subtree", node: main, want: []string{"source a.avif 800w", "img a.jpg 800w"}},
+		{name: "Lazy attributes", node: main, opts: []Option{WithAttributeNames(LazyAttributeNames)}, want: []string{"source a.avif 800w", "img a.jpg 800w", "img "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range ExtractFromNode(tt.node, tt.opts...) {
+				got = append(got, d.Node.Data+" "+d.Srcset.String())
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("%q. ExtractFromNode() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	decls := ExtractFromNode(main)
+	if d := decls[0]; d.Type != "image/avif" || d.Media != "(min-width: 800px)" || d.Sizes.String() != "50vw" {
+		t.Errorf("ExtractFromNode() source = %+v, want type, media and sizes", d)
+	}
+	if d := decls[1]; d.Src != "a.jpg" {
+		t.Errorf("ExtractFromNode() img src = %q, want a.jpg", d.Src)
+	}
+}
@@ -0,0 +1,57 @@
+package srcset
+
+import "math"
+
+// FallbackPolicy picks the candidate of a set to use as the src attribute,
+// for clients that do not support srcset.
+type FallbackPolicy func(s SourceSet) (ImageSource, bool)
+
+var (
+	// DefaultFallback picks the largest candidate of a set with width
+	// descriptors, and the candidate BestForDPR picks for 1x otherwise.
+	DefaultFallback FallbackPolicy = func(s SourceSet) (ImageSource, bool) {
+		if len(s.Widths()) > 0 {
+			return s.Largest()
+		}
+		return s.BestForDPR(1)
+	}
+
+	// FallbackLargest picks the largest candidate. See SourceSet.Largest.
+	FallbackLargest FallbackPolicy = SourceSet.Largest
+
+	// FallbackSmallest picks the smallest candidate. See SourceSet.Smallest.
+	FallbackSmallest FallbackPolicy = SourceSet.Smallest
+)
+
+// FallbackClosestWidth picks the width candidate whose width is closest to
+// target, preferring the larger one on a tie.
+func FallbackClosestWidth(target int64) FallbackPolicy {
+	return func(s SourceSet) (ImageSource, bool) {
+		var (
+			best     ImageSource
+			bestDiff = math.Inf(1)
+			bestW    int64
+			found    bool
+		)
+		for _, src := range s {
+			c := src.Candidate()
+			if !c.HasWidth {
+				continue
+			}
+			diff := math.Abs(float64(c.Width - target))
+			if diff < bestDiff || (diff == bestDiff && c.Width > bestW) {
+				best, bestDiff, bestW, found = src, diff, c.Width, true
+			}
+		}
+		return best, found
+	}
+}
+
+// Fallback returns the candidate picked by policy, or by DefaultFallback if
+// policy is nil.
+func (s SourceSet) Fallback(policy FallbackPolicy) (ImageSource, bool) {
+	if policy == nil {
+		policy = DefaultFallback
+	}
+	return policy(s)
+}
@@ -0,0 +1,32 @@
+package srcset
+
+import "testing"
+
+func Test_Fallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		policy FallbackPolicy
+		want   string
+		wantOk bool
+	}{
+		{name: "Default with densities", input: "a.png 2x, b.png, c.png 3x", want: "b.png", wantOk: true},
+		{name: "Default with widths", input: "a.png 640w, b.png 1280w, c.png 320w", want: "b.png", wantOk: true},
+		{name: "Default without 1x", input: "a.png 2x, b.png 3x", want: "a.png", wantOk: true},
+		{name: "Largest", input: "a.png 2x, b.png, c.png 3x", policy: FallbackLargest, want: "c.png", wantOk: true},
+		{name: "Smallest", input: "a.png 640w, b.png 1280w, c.png 320w", policy: FallbackSmallest, want: "c.png", wantOk: true},
+		{name: "Closest width", input: "a.png 640w, b.png 1280w, c.png 320w", policy: FallbackClosestWidth(1000), want: "b.png", wantOk: true},
+		{name: "Closest width tie", input: "a.png 600w, b.png 800w", policy: FallbackClosestWidth(700), want: "b.png", wantOk: true},
+		{name: "Closest width without widths", input: "a.png 1x", policy: FallbackClosestWidth(700)},
+		{name: "Empty", input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.input).Fallback(tt.policy)
+			if got.URL != tt.want || ok != tt.wantOk {
+				t.Errorf("%q. Fallback() = %q, %v, want %q, %v", tt.name, got.URL, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
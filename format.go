@@ -0,0 +1,58 @@
+package srcset
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	// Indent is written at the start of every line.
+	Indent string
+	// PercentEncode percent-encodes the characters of URLs that would not
+	// parse back, as EncodedString does.
+	PercentEncode bool
+	// Sort writes the candidates in the canonical order of Sorted, so that
+	// the output does not depend on the order of the input.
+	Sort bool
+}
+
+// Format serializes set into a human-readable srcset attribute value with
+// one candidate per line, and the descriptors of all candidates aligned in a
+// single column. The result parses into the same candidates as set.String.
+func Format(set SourceSet, opts FormatOptions) string {
+	var (
+		b     strings.Builder
+		width int
+	)
+	if opts.Sort {
+		set = set.Sorted()
+	}
+
+	urls := make([]string, len(set))
+	for i, src := range set {
+		urls[i] = src.URL
+		if opts.PercentEncode {
+			urls[i] = string(appendEncodedURL(nil, src.URL))
+		}
+		if n := utf8.RuneCountInString(urls[i]); n > width {
+			width = n
+		}
+	}
+
+	for i, src := range set {
+		if i > 0 {
+			b.WriteString(",\n")
+		}
+		b.WriteString(opts.Indent)
+		b.WriteString(urls[i])
+
+		descriptors := appendDescriptors(nil, src)
+		if len(descriptors) > 0 {
+			b.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(urls[i])))
+			b.Write(descriptors)
+		}
+	}
+
+	return b.String()
+}
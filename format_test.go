@@ -0,0 +1,79 @@
+package srcset
+
+import "testing"
+
+func Test_Format(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  FormatOptions
+		want  string
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "Single candidate",
+			input: "a.png 1x",
+			want:  "a.png 1x",
+		},
+		{
+			name:  "Aligned",
+			input: "small.jpg 320w, medium.jpg 640w 480h, large.jpg 1280w",
+			want: "small.jpg  320w,\n" +
+				"medium.jpg 640w 480h,\n" +
+				"large.jpg  1280w",
+		},
+		{
+			name:  "Indent",
+			input: "a.png, hi-res.png 2x",
+			opts:  FormatOptions{Indent: "\t"},
+			want: "\ta.png,\n" +
+				"\thi-res.png 2x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := Parse(tt.input)
+			got := Format(set, tt.opts)
+			if got != tt.want {
+				t.Errorf("%q. Format() = %q, want %q", tt.name, got, tt.want)
+			}
+			if !Parse(got).Equal(set) {
+				t.Errorf("%q. Format() = %q does not parse into %q", tt.name, got, set)
+			}
+		})
+	}
+}
+
+func Test_Format_percentEncode(t *testing.T) {
+	set := SourceSet{
+		{URL: "my image.png", Width: i(320)},
+		{URL: "b.png", Width: i(640)},
+	}
+
+	got := Format(set, FormatOptions{PercentEncode: true})
+	want := "my%20image.png 320w,\n" +
+		"b.png          640w"
+	if got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if urls := Parse(got).URLs(); len(urls) != 2 || urls[0] != "my%20image.png" {
+		t.Errorf("Format() = %q parses into %q", got, urls)
+	}
+}
+
+func Test_Format_sort(t *testing.T) {
+	set := Parse("large.jpg 1280w, small.jpg 320w, medium.jpg 640w")
+
+	got := Format(set, FormatOptions{Sort: true})
+	want := "small.jpg  320w,\n" +
+		"medium.jpg 640w,\n" +
+		"large.jpg  1280w"
+	if got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+package srcset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format implements fmt.Formatter. The %v and %s verbs print the attribute
+// value as String does, and %q quotes it. The %+v verb prints a table with a
+// row per candidate, listing its descriptors and kind, and %#v prints a Go
+// expression that evaluates to s.
+func (s SourceSet) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		f.Write([]byte("srcset.SourceSet{"))
+		for i, src := range s {
+			if i > 0 {
+				f.Write([]byte(", "))
+			}
+			f.Write([]byte(src.goString()))
+		}
+		f.Write([]byte("}"))
+	case verb == 'v' && f.Flag('+'):
+		tw := tabwriter.NewWriter(f, 0, 0, 2, ' ', 0)
+		fmt.Fprint(tw, "URL\tWIDTH\tHEIGHT\tDENSITY\tKIND")
+		for _, src := range s {
+			c := src.Candidate()
+			fmt.Fprintf(tw, "\n%s\t%s\t%s\t%s\t%s", src.URL,
+				optional(c.HasWidth, strconv.FormatInt(c.Width, 10)+"w"),
+				optional(c.HasHeight, strconv.FormatInt(c.Height, 10)+"h"),
+				optional(c.HasDensity, formatFloat(c.Density)+"x"),
+				src.Kind())
+		}
+		tw.Flush()
+	default:
+		formatString(f, verb, "SourceSet", s.String())
+	}
+}
+
+// Format implements fmt.Formatter. The %v and %s verbs print the candidate
+// as String does, and %q quotes it. The %+v verb lists its URL, descriptors
+// and kind by name, and %#v prints a Go expression that evaluates to src.
+func (src ImageSource) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		f.Write([]byte(src.goString()))
+	case verb == 'v' && f.Flag('+'):
+		c := src.Candidate()
+		fmt.Fprintf(f, "{URL:%s Width:%s Height:%s Density:%s Kind:%s}", src.URL,
+			optional(c.HasWidth, strconv.FormatInt(c.Width, 10)),
+			optional(c.HasHeight, strconv.FormatInt(c.Height, 10)),
+			optional(c.HasDensity, formatFloat(c.Density)),
+			src.Kind())
+	default:
+		formatString(f, verb, "ImageSource", src.String())
+	}
+}
+
+// goString returns src as a Go composite literal, with pointers written as
+// expressions yielding a pointer to their value rather than as addresses.
+func (src ImageSource) goString() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "srcset.ImageSource{URL:%q", src.URL)
+	if src.Width != nil {
+		fmt.Fprintf(&sb, ", Width:&[]int64{%d}[0]", *src.Width)
+	}
+	if src.Height != nil {
+		fmt.Fprintf(&sb, ", Height:&[]int64{%d}[0]", *src.Height)
+	}
+	if src.Density != nil {
+		fmt.Fprintf(&sb, ", Density:&[]float64{%s}[0]", strconv.FormatFloat(*src.Density, 'g', -1, 64))
+	}
+	if src.Offset != 0 {
+		fmt.Fprintf(&sb, ", Offset:%d", src.Offset)
+	}
+	if src.Extensions != nil {
+		fmt.Fprintf(&sb, ", Extensions:%#v", src.Extensions)
+	}
+	if src.RawDescriptors != nil {
+		fmt.Fprintf(&sb, ", RawDescriptors:%#v", src.RawDescriptors)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// formatString formats the serialization s of a value of the named type for
+// the verbs %v, %s and %q, honoring the flags, width and precision as for a
+// string, and reports other verbs as fmt does.
+func formatString(f fmt.State, verb rune, typ, s string) {
+	switch verb {
+	case 'v', 's', 'q':
+		fmt.Fprintf(f, directive(f, verb), s)
+	default:
+		fmt.Fprintf(f, "%%!%c(srcset.%s=%s)", verb, typ, s)
+	}
+}
+
+// directive rebuilds the formatting directive, such as "%-20.5s", that f and
+// verb were parsed from.
+func directive(f fmt.State, verb rune) string {
+	var sb strings.Builder
+	sb.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			sb.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		sb.WriteString(strconv.Itoa(width))
+	}
+	if prec, ok := f.Precision(); ok {
+		sb.WriteByte('.')
+		sb.WriteString(strconv.Itoa(prec))
+	}
+	sb.WriteRune(verb)
+	return sb.String()
+}
+
+// optional returns value if ok, and "-" otherwise.
+func optional(ok bool, value string) string {
+	if ok {
+		return value
+	}
+	return "-"
+}
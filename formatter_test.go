@@ -0,0 +1,65 @@
+package srcset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_SourceSet_Format(t *testing.T) {
+	set := Parse("a.png 320w 240h, b.png 2x, c.png", WithDescriptorHandler('q', quality))
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "Value", format: "%v", want: "a.png 320w 240h, b.png 2x, c.png"},
+		{name: "String", format: "%s", want: "a.png 320w 240h, b.png 2x, c.png"},
+		{name: "Quoted", format: "%q", want: `"a.png 320w 240h, b.png 2x, c.png"`},
+		{name: "Padded", format: "%-35s|", want: "a.png 320w 240h, b.png 2x, c.png   |"},
+		{name: "Table", format: "%+v", want: "URL    WIDTH  HEIGHT  DENSITY  KIND\n" +
+			"a.png  320w   240h    -        width\n" +
+			"b.png  -      -       2x       density\n" +
+			"c.png  -      -       -        default"},
+		{name: "Go syntax", format: "%#v", want: `srcset.SourceSet{` +
+			`srcset.ImageSource{URL:"a.png", Width:&[]int64{320}[0], Height:&[]int64{240}[0]}, ` +
+			`srcset.ImageSource{URL:"b.png", Density:&[]float64{2}[0], Offset:17}, ` +
+			`srcset.ImageSource{URL:"c.png", Offset:27}}`},
+		{name: "Bad verb", format: "%d", want: "%!d(srcset.SourceSet=a.png 320w 240h, b.png 2x, c.png)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, set); got != tt.want {
+				t.Errorf("%q. Sprintf(%q) = %q, want %q", tt.name, tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ImageSource_Format(t *testing.T) {
+	src := ImageSource{URL: "a.png", Density: fl(1.5), Extensions: map[string]string{"q": "80"}, RawDescriptors: []string{"future(1)"}}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "Value", format: "%v", want: "a.png 1.5x 80q future(1)"},
+		{name: "Padded", format: "%-30s|", want: "a.png 1.5x 80q future(1)      |"},
+		{name: "Right-aligned", format: "%26v", want: "  a.png 1.5x 80q future(1)"},
+		{name: "Precision", format: "%.5s", want: "a.png"},
+		{name: "Quoted with width", format: "%-10.3q|", want: `"a.p"     |`},
+		{name: "Fields", format: "%+v", want: "{URL:a.png Width:- Height:- Density:1.5 Kind:density}"},
+		{name: "Go syntax", format: "%#v", want: `srcset.ImageSource{URL:"a.png", Density:&[]float64{1.5}[0], Extensions:map[string]string{"q":"80"}, RawDescriptors:[]string{"future(1)"}}`},
+		{name: "Bad verb", format: "%x", want: "%!x(srcset.ImageSource=a.png 1.5x 80q future(1))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, src); got != tt.want {
+				t.Errorf("%q. Sprintf(%q) = %q, want %q", tt.name, tt.format, got, tt.want)
+			}
+		})
+	}
+}
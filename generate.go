@@ -0,0 +1,57 @@
+package srcset
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+const urlChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:?=&%;,()"
+
+// Random returns a random, valid SourceSet. The result serializes into a
+// srcset attribute that parses back into an equal SourceSet, which makes it
+// suitable for property-based tests of code that handles SourceSets.
+func Random(r *rand.Rand) SourceSet {
+	var (
+		n      = r.Intn(8)
+		set    = make(SourceSet, 0, n)
+		widths = r.Intn(2) == 0
+		width  int64
+		dens   float64
+	)
+
+	for i := 0; i < n; i++ {
+		src := ImageSource{URL: randomURL(r, i)}
+
+		// Widths and densities increase, so that no two candidates share one.
+		switch {
+		case widths:
+			width += r.Int63n(1024) + 1
+			w := width
+			src.Width = &w
+			if r.Intn(4) == 0 {
+				h := r.Int63n(4096) + 1
+				src.Height = &h
+			}
+		case i == 0 && r.Intn(2) == 0:
+			dens = 1
+		default:
+			dens += float64(r.Intn(300)+1) / float64(r.Intn(100)+1)
+			d := dens
+			src.Density = &d
+		}
+
+		set = append(set, src)
+	}
+
+	return set
+}
+
+// randomURL returns a URL that does not start or end with a comma. The index
+// i is included to keep URLs unique.
+func randomURL(r *rand.Rand, i int) string {
+	b := make([]byte, r.Intn(24))
+	for j := range b {
+		b[j] = urlChars[r.Intn(len(urlChars))]
+	}
+	return "img" + string(b) + "-" + strconv.Itoa(i) + ".png"
+}
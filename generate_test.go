@@ -0,0 +1,38 @@
+package srcset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		set := Random(r)
+		if v := set.Validate(); len(v) > 0 {
+			t.Fatalf("Random() = %q is invalid: %v", set, v)
+		}
+		if got := Parse(set.String()); !got.Equal(set) {
+			t.Fatalf("Parse(%q) = %q, want %q", set, got, set)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("image-1x.png 1x, image-2x.png 2x")
+	f.Add("elva-fairy-320w.jpg 320w,\n elva-fairy-480w.jpg 480w 200h")
+	f.Add("data:,a ( , data:,b 1x, ), data:,c")
+	f.Add("a.png,,, b.png 1x , c.png (")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		set := Parse(input)
+		for _, src := range set {
+			if src.Offset < 0 || src.Offset+len(src.URL) > len(input) || input[src.Offset:src.Offset+len(src.URL)] != src.URL {
+				t.Fatalf("Parse(%q): candidate %q has offset %d", input, src.URL, src.Offset)
+			}
+		}
+		if got := Parse(set.String()); !got.Equal(set) {
+			t.Fatalf("Parse(%q) = %q, reparsed as %q", input, set, got)
+		}
+	})
+}
@@ -1,3 +1,7 @@
 module github.com/lukasbob/srcset
 
-go 1.12
+go 1.18
+
+require golang.org/x/net v0.35.0
+
+require golang.org/x/text v0.22.0 // indirect
@@ -0,0 +1,135 @@
+package srcset
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HostPolicy describes which hosts candidate URLs may point at. Host
+// patterns are compared case-insensitively and without port; a pattern of
+// the form "*.example.com" matches any subdomain of example.com.
+type HostPolicy struct {
+	// Allow lists the approved hosts. If empty, all hosts that are not
+	// denied are approved.
+	Allow []string
+	// Deny lists hosts that are never approved.
+	Deny []string
+	// Rewrite is called for URLs pointing at unapproved hosts, and returns
+	// the URL to use instead, or an empty string to drop the candidate. If
+	// nil, such candidates are dropped.
+	Rewrite func(u *url.URL) string
+	// UpgradeHTTPS rewrites http URLs to https.
+	UpgradeHTTPS bool
+}
+
+// SanitizeHosts returns a copy of s in which the candidates pointing at
+// hosts not approved by policy are rewritten or dropped. Hosts are determined
+// the way browsers parse URLs: backslashes count as slashes, and the slashes
+// after the scheme of an http, https, ws, wss or ftp URL are optional, so
+// that "https:\\evil.test/a.png" points at evil.test. Such URLs without a
+// host are dropped. Relative URLs without scheme and host are always
+// approved, while other URLs without host, such as data: URLs, are only
+// approved if Allow is empty. A trailing dot of a host or pattern is ignored,
+// as "evil.test." names the same host as "evil.test". Candidates whose URL cannot be parsed are dropped. Approved URLs are kept
+// as they are, apart from the scheme with UpgradeHTTPS.
+func (s SourceSet) SanitizeHosts(policy HostPolicy) SourceSet {
+	sanitized := SourceSet{}
+
+	for _, src := range s {
+		raw := src.URL
+		u, err := parseBrowserURL(raw)
+		if err != nil {
+			continue
+		}
+		if specialSchemes[strings.ToLower(u.Scheme)] && u.Host == "" {
+			continue
+		}
+
+		if (u.Scheme != "" || u.Host != "") && !policy.approves(u.Hostname()) {
+			if policy.Rewrite == nil {
+				continue
+			}
+			raw = policy.Rewrite(u)
+			if raw == "" {
+				continue
+			}
+			if u, err = parseBrowserURL(raw); err != nil {
+				continue
+			}
+		}
+
+		if policy.UpgradeHTTPS && strings.EqualFold(u.Scheme, "http") {
+			raw = "https" + raw[strings.IndexByte(raw, ':'):]
+		}
+
+		src = src.Clone()
+		src.URL = raw
+		sanitized = append(sanitized, src)
+	}
+
+	return sanitized
+}
+
+// specialSchemes are the schemes with a host whose URLs browsers parse
+// leniently.
+var specialSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"ws":    true,
+	"wss":   true,
+	"ftp":   true,
+}
+
+// parseBrowserURL parses raw into the URL a browser would request, as far as
+// its host is concerned: tabs and newlines are removed, backslashes before
+// the query are taken as slashes, and the URL of a special scheme is given
+// its authority even if the slashes after the scheme are missing.
+func parseBrowserURL(raw string) (*url.URL, error) {
+	raw = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, strings.Trim(raw, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1a\x1b\x1c\x1d\x1e\x1f "))
+
+	end := strings.IndexAny(raw, "?#")
+	if end < 0 {
+		end = len(raw)
+	}
+	raw = strings.ReplaceAll(raw[:end], "\\", "/") + raw[end:]
+
+	if i := strings.IndexByte(raw, ':'); i > 0 && specialSchemes[strings.ToLower(raw[:i])] {
+		raw = raw[:i] + "://" + strings.TrimLeft(raw[i+1:], "/")
+	}
+	return url.Parse(raw)
+}
+
+func (p HostPolicy) approves(host string) bool {
+	for _, pattern := range p.Deny {
+		if matchHost(pattern, host) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if matchHost(pattern, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHost matches host against pattern, comparing the ASCII forms of
+// internationalized hostnames, so that bücher.de matches xn--bcher-kva.de,
+// and ignoring trailing dots of fully qualified names.
+func matchHost(pattern, host string) bool {
+	pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+	host = toASCIIHost(strings.TrimSuffix(strings.ToLower(host), "."))
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, "."+toASCIIHost(pattern[2:]))
+	}
+	pattern = toASCIIHost(pattern)
+	return pattern == host
+}
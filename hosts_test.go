@@ -0,0 +1,116 @@
+package srcset
+
+import (
+	"net/url"
+	"testing"
+)
+
+func Test_SanitizeHosts(t *testing.T) {
+	const input = "a.png 1x, https://cdn.example.com/b.png 2x, http://img.Example.com:8080/c.png 3x, https://evil.test/d.png 4x"
+
+	tests := []struct {
+		name   string
+		input  string
+		policy HostPolicy
+		want   string
+	}{
+		{
+			name:   "No policy",
+			policy: HostPolicy{},
+			want:   input,
+		},
+		{
+			name:   "Allow list",
+			policy: HostPolicy{Allow: []string{"*.example.com"}},
+			want:   "a.png 1x, https://cdn.example.com/b.png 2x, http://img.Example.com:8080/c.png 3x",
+		},
+		{
+			name:   "Deny list",
+			policy: HostPolicy{Allow: []string{"*.example.com"}, Deny: []string{"IMG.example.com"}},
+			want:   "a.png 1x, https://cdn.example.com/b.png 2x",
+		},
+		{
+			name:   "Upgrade HTTPS",
+			policy: HostPolicy{Deny: []string{"evil.test"}, UpgradeHTTPS: true},
+			want:   "a.png 1x, https://cdn.example.com/b.png 2x, https://img.Example.com:8080/c.png 3x",
+		},
+		{
+			name: "Rewrite",
+			policy: HostPolicy{
+				Allow: []string{"cdn.example.com"},
+				Rewrite: func(u *url.URL) string {
+					if u.Hostname() == "evil.test" {
+						return ""
+					}
+					return "https://cdn.example.com/proxy?url=" + url.QueryEscape(u.String())
+				},
+			},
+			want: "a.png 1x, https://cdn.example.com/b.png 2x, https://cdn.example.com/proxy?url=http%3A%2F%2Fimg.Example.com%3A8080%2Fc.png 3x",
+		},
+		{
+			name:   "Protocol-relative URL",
+			input:  "a.png 1x, //evil.test/b.png 2x",
+			policy: HostPolicy{Allow: []string{"example.com"}},
+			want:   "a.png 1x",
+		},
+		{
+			name:   "Internationalized hosts",
+			input:  "https://xn--bcher-kva.de/a.png 1x, https://img.bücher.de/b.png 2x, https://buecher.de/c.png 3x",
+			policy: HostPolicy{Allow: []string{"bücher.de", "*.XN--BCHER-KVA.DE"}},
+			want:   "https://xn--bcher-kva.de/a.png 1x, https://img.bücher.de/b.png 2x",
+		},
+		{
+			name:   "Backslashes",
+			input:  "https://cdn.example.com/a.png 1x, https:\\\\evil.test/b.png 2x, \\\\evil.test/c.png 3x, /\\evil.test/d.png 4x",
+			policy: HostPolicy{Allow: []string{"cdn.example.com"}},
+			want:   "https://cdn.example.com/a.png 1x",
+		},
+		{
+			name:   "Missing slashes",
+			input:  "https:/evil.test/a.png 1x, https:evil.test/b.png 2x, https:cdn.example.com/c.png 3x",
+			policy: HostPolicy{Allow: []string{"cdn.example.com"}},
+			want:   "https:cdn.example.com/c.png 3x",
+		},
+		{
+			name:   "Special scheme without host",
+			input:  "https:/// 1x, http:?a 2x, data:image/gif;base64,R0lG 3x",
+			policy: HostPolicy{},
+			want:   "data:image/gif;base64,R0lG 3x",
+		},
+		{
+			name:   "Trailing dots",
+			input:  "https://evil.test./a.png 1x, https://a.evil.test./b.png 2x, https://cdn.example.com./c.png 3x",
+			policy: HostPolicy{Deny: []string{"evil.test", "*.evil.test"}},
+			want:   "https://cdn.example.com./c.png 3x",
+		},
+		{
+			name:   "Trailing dots in patterns",
+			input:  "https://evil.test/a.png 1x, https://a.evil.test/b.png 2x, https://cdn.example.com/c.png 3x",
+			policy: HostPolicy{Allow: []string{"cdn.example.com."}, Deny: []string{"*.evil.test."}},
+			want:   "https://cdn.example.com/c.png 3x",
+		},
+		{
+			name:   "Scheme without host",
+			input:  "a.png 1x, javascript:alert(1) 2x, data:image/gif;base64,R0lG 3x, https://cdn.example.com/d.png 4x",
+			policy: HostPolicy{Allow: []string{"cdn.example.com"}},
+			want:   "a.png 1x, https://cdn.example.com/d.png 4x",
+		},
+		{
+			name:   "Kept as written",
+			input:  "/a/\\x.png?q=%7e 1x, HTTP://cdn.example.com/%7Ea.png 2x",
+			policy: HostPolicy{Allow: []string{"cdn.example.com"}, UpgradeHTTPS: true},
+			want:   "/a/\\x.png?q=%7e 1x, https://cdn.example.com/%7Ea.png 2x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.input == "" {
+				tt.input = input
+			}
+			if got := Parse(tt.input).SanitizeHosts(tt.policy).String(); got != tt.want {
+				t.Errorf("%q. SanitizeHosts() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
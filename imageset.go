@@ -0,0 +1,457 @@
+package srcset
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ImageSetOption is an option of a CSS image-set(), such as
+// "b.png" 2x type("image/png"). It has the same fields as an ImageSource
+// with a density descriptor.
+type ImageSetOption struct {
+	URL     string
+	Density *float64 // the resolution in x, nil if absent, which means 1x
+	Type    string   // the MIME type of type(), empty if absent
+	Offset  int
+}
+
+// ImageSet is the result of parsing a CSS image-set().
+type ImageSet []ImageSetOption
+
+var errUnterminated = errors.New("unterminated string or function")
+
+// ParseImageSet takes a CSS image-set() and parses it. The image-set( and
+// closing parenthesis may be omitted, and -webkit-image-set( is accepted.
+// Images are strings or url() functions. Resolutions are converted to x,
+// from dppx, dpi and dpcm. Options that cannot be parsed are skipped and
+// reported to the warning handler, with offsets relative to input.
+func ParseImageSet(input string, opts ...Option) ImageSet {
+	var (
+		cfg       = newConfig(opts)
+		set       = ImageSet{}
+		body, off = unwrapImageSet(input)
+		at        = NewPositioner(input)
+	)
+
+	spans, err := splitCSS(body)
+	if err != nil {
+		cfg.report(at, Suspicious, off, body, "unterminated string or function", ErrSyntax)
+	}
+
+	for _, sp := range spans {
+		raw := body[sp.start:sp.end]
+		text := strings.TrimSpace(raw)
+		offset := off + sp.start + strings.Index(raw, text)
+		if text == "" {
+			cfg.report(at, SkippedGarbage, off+sp.start, raw, "empty option", ErrSyntax)
+			continue
+		}
+
+		opt, message, err := parseImageSetOption(text)
+		if err != nil {
+			cfg.report(at, DroppedCandidate, offset, text, message, err)
+			continue
+		}
+		opt.Offset = offset
+		set = append(set, opt)
+	}
+
+	return set
+}
+
+// unwrapImageSet returns the arguments of an image-set() function and their
+// offset in input, or input itself if it is not an image-set() function.
+func unwrapImageSet(input string) (string, int) {
+	trimmed := strings.TrimSpace(input)
+	start := strings.Index(input, trimmed)
+	lower := strings.ToLower(trimmed)
+
+	for _, prefix := range []string{"image-set(", "-webkit-image-set("} {
+		if strings.HasPrefix(lower, prefix) {
+			body := trimmed[len(prefix):]
+			if strings.HasSuffix(body, ")") {
+				body = body[:len(body)-1]
+			}
+			return body, start + len(prefix)
+		}
+	}
+	return input, 0
+}
+
+// splitCSS splits input at commas that are not nested in parentheses or
+// strings. An error is returned along with the spans if a string or
+// parenthesis is not terminated.
+func splitCSS(input string) ([]span, error) {
+	var (
+		spans []span
+		depth int
+		start int
+		quote byte
+	)
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '\\':
+			i++
+		case c == leftParens:
+			depth++
+		case c == rightParens:
+			if depth > 0 {
+				depth--
+			}
+		case c == comma && depth == 0:
+			spans = append(spans, span{start, i})
+			start = i + 1
+		}
+	}
+
+	if strings.TrimSpace(input[start:]) != "" || len(spans) > 0 {
+		spans = append(spans, span{start, len(input)})
+	}
+
+	if quote != 0 || depth > 0 {
+		return spans, errUnterminated
+	}
+	return spans, nil
+}
+
+// cssToken is a string, a function with its raw arguments, or any other run
+// of characters up to whitespace.
+type cssToken struct {
+	kind  byte   // '"' for strings, '(' for functions, 0 otherwise
+	name  string // the lowercased name of a function
+	value string // the unescaped string, the arguments or the text
+}
+
+func tokenizeCSS(input string) ([]cssToken, error) {
+	var toks []cssToken
+
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case isSpace(rune(c)):
+			i++
+
+		case c == '"' || c == '\'':
+			value, n, err := readCSSString(input[i:])
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, cssToken{kind: '"', value: value})
+			i += n
+
+		default:
+			j := i
+			for j < len(input) && !isSpace(rune(input[j])) && input[j] != leftParens {
+				j++
+			}
+			if j == len(input) || input[j] != leftParens {
+				toks = append(toks, cssToken{value: input[i:j]})
+				i = j
+				break
+			}
+
+			// A function: find the matching parenthesis, skipping strings.
+			depth, k := 0, j
+			for ; k < len(input); k++ {
+				switch input[k] {
+				case '"', '\'':
+					_, n, err := readCSSString(input[k:])
+					if err != nil {
+						return nil, err
+					}
+					k += n - 1
+				case leftParens:
+					depth++
+				case rightParens:
+					depth--
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			if k == len(input) {
+				return nil, errUnterminated
+			}
+			toks = append(toks, cssToken{kind: '(', name: strings.ToLower(input[i:j]), value: input[j+1 : k]})
+			i = k + 1
+		}
+	}
+
+	return toks, nil
+}
+
+// readCSSString reads the quoted string at the start of input, and returns
+// its unescaped value and length.
+func readCSSString(input string) (string, int, error) {
+	var (
+		sb    strings.Builder
+		quote = input[0]
+	)
+
+	for i := 1; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == quote:
+			return sb.String(), i + 1, nil
+		case c == '\n':
+			return "", 0, errUnterminated
+		case c == '\\' && i+1 < len(input):
+			n := readCSSEscape(&sb, input[i+1:])
+			i += n
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", 0, errUnterminated
+}
+
+// readCSSEscape writes the character escaped by input, which follows a
+// backslash, and returns the number of bytes consumed.
+func readCSSEscape(sb *strings.Builder, input string) int {
+	if input[0] == '\n' {
+		return 1
+	}
+
+	n := 0
+	for n < len(input) && n < 6 && isHex(input[n]) {
+		n++
+	}
+	if n == 0 {
+		r, size := utf8.DecodeRuneInString(input)
+		sb.WriteRune(r)
+		return size
+	}
+
+	code, _ := strconv.ParseUint(input[:n], 16, 32)
+	if code == 0 || code > utf8.MaxRune || 0xd800 <= code && code <= 0xdfff {
+		code = utf8.RuneError
+	}
+	sb.WriteRune(rune(code))
+	if n < len(input) && isSpace(rune(input[n])) {
+		n++
+	}
+	return n
+}
+
+// parseImageSetOption parses a single option of an image-set(), returning a
+// message and error if it is invalid.
+func parseImageSetOption(text string) (ImageSetOption, string, error) {
+	var opt ImageSetOption
+
+	toks, err := tokenizeCSS(text)
+	if err != nil {
+		return opt, "unterminated string or function", ErrSyntax
+	}
+
+	switch first := toks[0]; {
+	case first.kind == '"':
+		opt.URL = first.value
+	case first.kind == '(' && first.name == "url":
+		opt.URL = strings.TrimSpace(first.value)
+		if opt.URL != "" && (opt.URL[0] == '"' || opt.URL[0] == '\'') {
+			value, n, err := readCSSString(opt.URL)
+			if err != nil || n != len(opt.URL) {
+				return opt, "invalid url()", ErrInvalidURL
+			}
+			opt.URL = value
+		}
+	default:
+		return opt, "unsupported image", ErrInvalidURL
+	}
+
+	var (
+		hasResolution bool
+		hasType       bool
+	)
+
+	for _, tok := range toks[1:] {
+		switch {
+		case tok.kind == '(' && tok.name == "type":
+			args, err := tokenizeCSS(tok.value)
+			if err != nil || len(args) != 1 || args[0].kind != '"' {
+				return opt, "invalid type()", ErrInvalidDescriptor
+			}
+			if hasType {
+				return opt, "duplicate type()", ErrDuplicateDescriptor
+			}
+			opt.Type, hasType = args[0].value, true
+		case tok.kind == 0:
+			d, err := parseResolution(tok.value)
+			if err != nil {
+				return opt, "invalid resolution", err
+			}
+			if hasResolution {
+				return opt, "duplicate resolution", ErrDuplicateDescriptor
+			}
+			opt.Density, hasResolution = &d, true
+		default:
+			return opt, "invalid descriptor", ErrInvalidDescriptor
+		}
+	}
+
+	return opt, "", nil
+}
+
+// parseResolution parses a CSS resolution and returns it in x.
+func parseResolution(text string) (float64, error) {
+	lower := strings.ToLower(text)
+
+	var (
+		number string
+		factor float64
+	)
+	switch {
+	case strings.HasSuffix(lower, "dppx"):
+		number, factor = lower[:len(lower)-4], 1
+	case strings.HasSuffix(lower, "dpcm"):
+		number, factor = lower[:len(lower)-4], 2.54/96
+	case strings.HasSuffix(lower, "dpi"):
+		number, factor = lower[:len(lower)-3], 1.0/96
+	case strings.HasSuffix(lower, "x"):
+		number, factor = lower[:len(lower)-1], 1
+	default:
+		return 0, ErrInvalidDescriptor
+	}
+
+	if strings.HasPrefix(number, "+") {
+		number = number[1:]
+	}
+	if !regexFloatingPoint.MatchString(number) {
+		return 0, ErrInvalidDescriptor
+	}
+	d, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, ErrInvalidDescriptor
+	}
+	if d <= 0 {
+		return 0, ErrZeroDensity
+	}
+	return d * factor, nil
+}
+
+// ToImageSet converts s to a CSS image-set(). Width candidates cannot be
+// expressed in image-set() and are dropped; convert them with ToDensitySet
+// first to keep them. Custom and raw descriptors are removed. Each dropped
+// candidate and removed descriptor is reported as a violation.
+func (s SourceSet) ToImageSet() (ImageSet, []Violation) {
+	var (
+		set        = make(ImageSet, 0, len(s))
+		violations []Violation
+	)
+
+	report := func(src ImageSource, message string) {
+		violations = append(violations, Violation{Offset: src.Offset, URL: src.URL, Message: message, Err: ErrUnsupported})
+	}
+
+	for _, src := range s {
+		if src.Width != nil || src.Height != nil {
+			report(src, "width and height descriptors are not supported in image-set()")
+			continue
+		}
+		if len(src.Extensions) > 0 || len(src.RawDescriptors) > 0 {
+			report(src, "custom descriptors are not supported in image-set()")
+		}
+
+		opt := ImageSetOption{URL: src.URL, Offset: src.Offset}
+		if src.Density != nil {
+			d := *src.Density
+			opt.Density = &d
+		}
+		set = append(set, opt)
+	}
+
+	return set, violations
+}
+
+// SourceSet converts s to a srcset. The type() of an option cannot be
+// expressed in srcset, so it is removed and reported as a violation; use a
+// picture element with a source per type instead.
+func (s ImageSet) SourceSet() (SourceSet, []Violation) {
+	var (
+		set        = make(SourceSet, 0, len(s))
+		violations []Violation
+	)
+
+	for _, opt := range s {
+		if opt.Type != "" {
+			violations = append(violations, Violation{
+				Offset:  opt.Offset,
+				URL:     opt.URL,
+				Message: "type() " + strconv.Quote(opt.Type) + " is not supported in srcset",
+				Err:     ErrUnsupported,
+			})
+		}
+
+		src := ImageSource{URL: opt.URL, Offset: opt.Offset}
+		if opt.Density != nil {
+			d := *opt.Density
+			src.Density = &d
+		}
+		set = append(set, src)
+	}
+
+	return set, violations
+}
+
+// String serializes the ImageSet as a CSS image-set() function.
+func (s ImageSet) String() string {
+	var sb strings.Builder
+	sb.WriteString("image-set(")
+	for i, opt := range s {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(opt.String())
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// String serializes the option, such as "b.png" 2x type("image/png").
+func (opt ImageSetOption) String() string {
+	var sb strings.Builder
+	writeCSSString(&sb, opt.URL)
+	if opt.Density != nil {
+		sb.WriteByte(' ')
+		sb.WriteString(formatFloat(*opt.Density))
+		sb.WriteByte('x')
+	}
+	if opt.Type != "" {
+		sb.WriteString(" type(")
+		writeCSSString(&sb, opt.Type)
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// writeCSSString writes s as a double-quoted CSS string.
+func writeCSSString(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			sb.WriteByte('\\')
+			sb.WriteString(strconv.FormatInt(int64(r), 16))
+			sb.WriteByte(' ')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+}
@@ -0,0 +1,171 @@
+package srcset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_ParseImageSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     ImageSet
+		wantErrs []error
+	}{
+		{
+			name:  "Strings",
+			input: `image-set("a.png" 1x, "b.png" 2x type("image/png"))`,
+			want: ImageSet{
+				{URL: "a.png", Density: fl(1), Offset: 10},
+				{URL: "b.png", Density: fl(2), Type: "image/png", Offset: 22},
+			},
+		},
+		{
+			name:  "Without function",
+			input: `'a.png', url(b.png) 2X`,
+			want: ImageSet{
+				{URL: "a.png", Offset: 0},
+				{URL: "b.png", Density: fl(2), Offset: 9},
+			},
+		},
+		{
+			name:  "Prefixed",
+			input: ` -WEBKIT-IMAGE-SET(url("a.png") 1x) `,
+			want: ImageSet{
+				{URL: "a.png", Density: fl(1), Offset: 19},
+			},
+		},
+		{
+			name:  "Resolution units",
+			input: `image-set("a.png" 2dppx, "b.png" 192dpi, "c.png" 2.54dpcm, "d.png" +1.5x)`,
+			want: ImageSet{
+				{URL: "a.png", Density: fl(2), Offset: 10},
+				{URL: "b.png", Density: fl(2), Offset: 25},
+				{URL: "c.png", Density: fl(1.0 / 96 * 2.54 * 2.54), Offset: 41},
+				{URL: "d.png", Density: fl(1.5), Offset: 59},
+			},
+		},
+		{
+			name:  "Type before resolution",
+			input: `image-set("a.avif" type("image/avif") 2x)`,
+			want: ImageSet{
+				{URL: "a.avif", Density: fl(2), Type: "image/avif", Offset: 10},
+			},
+		},
+		{
+			name:  "Escapes and commas in strings",
+			input: `image-set("a\"b,c.png" 1x, url('d\2c e.png') 2x)`,
+			want: ImageSet{
+				{URL: `a"b,c.png`, Density: fl(1), Offset: 10},
+				{URL: "d,e.png", Density: fl(2), Offset: 27},
+			},
+		},
+		{
+			name:     "Invalid options",
+			input:    `image-set("a.png" 1w, "b.png" 1x 2x, c.png 1x, linear-gradient(red, blue) 1x, "d.png" 0x, "e.png" 2x)`,
+			want:     ImageSet{{URL: "e.png", Density: fl(2), Offset: 90}},
+			wantErrs: []error{ErrInvalidDescriptor, ErrDuplicateDescriptor, ErrInvalidURL, ErrInvalidURL, ErrZeroDensity},
+		},
+		{
+			name:     "Empty option",
+			input:    `image-set("a.png" 1x,, "b.png" 2x)`,
+			want:     ImageSet{{URL: "a.png", Density: fl(1), Offset: 10}, {URL: "b.png", Density: fl(2), Offset: 23}},
+			wantErrs: []error{ErrSyntax},
+		},
+		{
+			name:     "Unterminated string",
+			input:    `image-set("a.png" 1x, "b.png 2x)`,
+			want:     ImageSet{{URL: "a.png", Density: fl(1), Offset: 10}},
+			wantErrs: []error{ErrSyntax, ErrSyntax},
+		},
+		{
+			name:  "Empty",
+			input: "image-set()",
+			want:  ImageSet{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			got := ParseImageSet(tt.input, WithWarningHandler(func(w Warning) { errs = append(errs, w.Err) }))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ParseImageSet() = %v, want %v", tt.name, got, tt.want)
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("%q. ParseImageSet() errors = %v, want %v", tt.name, errs, tt.wantErrs)
+			}
+			for i := range errs {
+				if !errors.Is(errs[i], tt.wantErrs[i]) {
+					t.Errorf("%q. ParseImageSet() errors[%d] = %v, want %v", tt.name, i, errs[i], tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_ImageSet_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Options", input: `image-set(url(a.png) 1x, "b.png" 2dppx type('image/png'))`, want: `image-set("a.png" 1x, "b.png" 2x type("image/png"))`},
+		{name: "Escapes", input: `image-set("a\"b\\c\a.png")`, want: `image-set("a\"b\\c\a .png")`},
+		{name: "Empty", input: ``, want: `image-set()`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseImageSet(tt.input)
+			if got.String() != tt.want {
+				t.Errorf("%q. String() = %q, want %q", tt.name, got, tt.want)
+			}
+			if again := ParseImageSet(got.String()); !reflect.DeepEqual(again.String(), got.String()) {
+				t.Errorf("%q. String() does not round-trip: %q", tt.name, again)
+			}
+		})
+	}
+}
+
+func Test_ToImageSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		want     string
+		wantErrs int
+	}{
+		{name: "Densities", input: "a.png, b.png 2x", want: `image-set("a.png", "b.png" 2x)`},
+		{name: "Widths dropped", input: "a.png 320w, b.png 640w 480h", want: `image-set()`, wantErrs: 2},
+		{name: "Custom descriptors removed", input: "a.png 2x 80q", opts: []Option{WithDescriptorHandler('q', quality)}, want: `image-set("a.png" 2x)`, wantErrs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, violations := Parse(tt.input, tt.opts...).ToImageSet()
+			if got.String() != tt.want {
+				t.Errorf("%q. ToImageSet() = %q, want %q", tt.name, got, tt.want)
+			}
+			if len(violations) != tt.wantErrs {
+				t.Errorf("%q. ToImageSet() violations = %v, want %d", tt.name, violations, tt.wantErrs)
+			}
+			for _, v := range violations {
+				if !errors.Is(v.Err, ErrUnsupported) {
+					t.Errorf("%q. ToImageSet() violation = %v, want ErrUnsupported", tt.name, v.Err)
+				}
+			}
+		})
+	}
+}
+
+func Test_ImageSet_SourceSet(t *testing.T) {
+	got, violations := ParseImageSet(`image-set("a.avif" type("image/avif"), "b.png" 192dpi)`).SourceSet()
+	if want := "a.avif, b.png 2x"; got.String() != want {
+		t.Errorf("SourceSet() = %q, want %q", got, want)
+	}
+	if len(violations) != 1 || violations[0].URL != "a.avif" || !errors.Is(violations[0].Err, ErrUnsupported) {
+		t.Errorf("SourceSet() violations = %v, want one for a.avif", violations)
+	}
+}
@@ -0,0 +1,102 @@
+package srcset
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+// ImgOptions holds the attributes of an img element other than srcset.
+type ImgOptions struct {
+	// Src is the fallback URL for clients that do not support srcset. If it
+	// is empty, the candidate picked by Fallback is used.
+	Src string
+
+	// Fallback picks the candidate for the src attribute if Src is empty. It
+	// defaults to DefaultFallback.
+	Fallback FallbackPolicy
+
+	// Sizes is required when the set has width descriptors.
+	Sizes SizeList
+
+	Alt      string
+	Width    int64  // intrinsic width; omitted when zero
+	Height   int64  // intrinsic height; omitted when zero
+	Loading  string // "lazy", "eager", or empty
+	Decoding string // "sync", "async", "auto", or empty
+
+	// Sort renders srcset attributes with the candidates in the canonical
+	// order of Sorted, so that generated markup is stable across runs.
+	Sort bool
+}
+
+// RenderImg renders an img element for set. The alt attribute is always
+// rendered, as an empty alt attribute marks the image as decorative.
+func RenderImg(set SourceSet, opts ImgOptions) (template.HTML, error) {
+	if len(set) == 0 && opts.Src == "" {
+		return "", errors.New("srcset: no candidates and no src")
+	}
+	if len(set.Widths()) > 0 && len(opts.Sizes) == 0 {
+		return "", errors.New("srcset: width descriptors require sizes")
+	}
+	switch opts.Loading {
+	case "", "lazy", "eager":
+	default:
+		return "", fmt.Errorf("srcset: invalid loading %q", opts.Loading)
+	}
+	switch opts.Decoding {
+	case "", "sync", "async", "auto":
+	default:
+		return "", fmt.Errorf("srcset: invalid decoding %q", opts.Decoding)
+	}
+
+	if opts.Sort {
+		set = set.Sorted()
+	}
+
+	src := opts.Src
+	if src == "" {
+		fallback, _ := set.Fallback(opts.Fallback)
+		src = fallback.URL
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<img src="`)
+	sb.WriteString(html.EscapeString(src))
+	sb.WriteByte('"')
+	if len(set) > 0 {
+		sb.WriteString(` srcset="`)
+		sb.WriteString(set.AttrString())
+		sb.WriteByte('"')
+	}
+	writeAttr(&sb, "sizes", opts.Sizes.String())
+	if opts.Width > 0 {
+		writeAttr(&sb, "width", strconv.FormatInt(opts.Width, 10))
+	}
+	if opts.Height > 0 {
+		writeAttr(&sb, "height", strconv.FormatInt(opts.Height, 10))
+	}
+	sb.WriteString(` alt="`)
+	sb.WriteString(html.EscapeString(opts.Alt))
+	sb.WriteByte('"')
+	writeAttr(&sb, "loading", opts.Loading)
+	writeAttr(&sb, "decoding", opts.Decoding)
+	sb.WriteByte('>')
+
+	return template.HTML(sb.String()), nil
+}
+
+// writeAttr writes an attribute with the escaped value, unless it is empty.
+func writeAttr(sb *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(name)
+	sb.WriteString(`="`)
+	sb.WriteString(html.EscapeString(value))
+	sb.WriteByte('"')
+}
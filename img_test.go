@@ -0,0 +1,95 @@
+package srcset
+
+import (
+	"html/template"
+	"testing"
+)
+
+func Test_RenderImg(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     SourceSet
+		opts    ImgOptions
+		want    template.HTML
+		wantErr bool
+	}{
+		{
+			name: "Density set",
+			set:  Parse("a.png 1x, b.png 2x"),
+			opts: ImgOptions{Alt: `"A" & B`},
+			want: `<img src="a.png" srcset="a.png 1x, b.png 2x" alt="&#34;A&#34; &amp; B">`,
+		},
+		{
+			name: "Width set with all attributes",
+			set:  Parse("a.png 320w, b.png 640w"),
+			opts: ImgOptions{
+				Sizes:    ParseSizes("(min-width: 640px) 50vw, 100vw"),
+				Width:    640,
+				Height:   480,
+				Loading:  "lazy",
+				Decoding: "async",
+			},
+			want: `<img src="b.png" srcset="a.png 320w, b.png 640w" sizes="(min-width: 640px) 50vw, 100vw" width="640" height="480" alt="" loading="lazy" decoding="async">`,
+		},
+		{
+			name: "Explicit src",
+			set:  Parse("a.png 1x, b.png 2x"),
+			opts: ImgOptions{Src: "fallback.png?a=1&b=2"},
+			want: `<img src="fallback.png?a=1&amp;b=2" srcset="a.png 1x, b.png 2x" alt="">`,
+		},
+		{
+			name: "Sorted",
+			set:  Parse("b.png 2x, a.png"),
+			opts: ImgOptions{Sort: true},
+			want: `<img src="a.png" srcset="a.png, b.png 2x" alt="">`,
+		},
+		{
+			name: "Only src",
+			opts: ImgOptions{Src: "a.png"},
+			want: `<img src="a.png" alt="">`,
+		},
+		{
+			name:    "No candidates",
+			wantErr: true,
+		},
+		{
+			name:    "Width set without sizes",
+			set:     Parse("a.png 320w"),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid loading",
+			set:     Parse("a.png"),
+			opts:    ImgOptions{Loading: "later"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid decoding",
+			set:     Parse("a.png"),
+			opts:    ImgOptions{Decoding: "fast"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderImg(tt.set, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. RenderImg() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("%q. RenderImg() = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RenderImg_fallback(t *testing.T) {
+	got, err := RenderImg(Parse("a.png 1x, b.png 2x"), ImgOptions{Fallback: FallbackLargest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := template.HTML(`<img src="b.png" srcset="a.png 1x, b.png 2x" alt="">`); got != want {
+		t.Errorf("RenderImg() = %s, want %s", got, want)
+	}
+}
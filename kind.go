@@ -0,0 +1,87 @@
+package srcset
+
+import "fmt"
+
+// DescriptorKind classifies a candidate, or a set of candidates, by its
+// descriptors.
+type DescriptorKind int
+
+const (
+	// KindDefault is a candidate without descriptors, which counts as 1x.
+	KindDefault DescriptorKind = iota
+	// KindWidth is a candidate with a width descriptor.
+	KindWidth
+	// KindDensity is a candidate with a density descriptor.
+	KindDensity
+	// KindMixed is a candidate with both width and density descriptors, or
+	// a height descriptor without width descriptor, or a set that mixes
+	// width candidates with other candidates. It is not conforming.
+	KindMixed
+)
+
+func (k DescriptorKind) String() string {
+	switch k {
+	case KindDefault:
+		return "default"
+	case KindWidth:
+		return "width"
+	case KindDensity:
+		return "density"
+	case KindMixed:
+		return "mixed"
+	default:
+		return fmt.Sprintf("DescriptorKind(%d)", int(k))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so that kinds are encoded
+// by name, such as "width".
+func (k DescriptorKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// IsWidthBased reports whether k is KindWidth.
+func (k DescriptorKind) IsWidthBased() bool {
+	return k == KindWidth
+}
+
+// IsDensityBased reports whether k is KindDensity or KindDefault.
+func (k DescriptorKind) IsDensityBased() bool {
+	return k == KindDensity || k == KindDefault
+}
+
+// Kind classifies src by its descriptors.
+func (src ImageSource) Kind() DescriptorKind {
+	switch {
+	case src.Width != nil && src.Density == nil:
+		return KindWidth
+	case src.Width != nil, src.Height != nil:
+		return KindMixed
+	case src.Density != nil:
+		return KindDensity
+	default:
+		return KindDefault
+	}
+}
+
+// Kind classifies s by the descriptors of its candidates: KindWidth if all
+// are width candidates, KindDensity if all are density or default
+// candidates and at least one is a density candidate, KindDefault if all are
+// default candidates or s is empty, and KindMixed otherwise.
+func (s SourceSet) Kind() DescriptorKind {
+	kind := KindDefault
+	for i, src := range s {
+		k := src.Kind()
+		switch {
+		case k == KindMixed:
+			return KindMixed
+		case i == 0:
+			kind = k
+		case k.IsWidthBased() != kind.IsWidthBased():
+			return KindMixed
+		case k == KindDensity:
+			kind = KindDensity
+		}
+	}
+	return kind
+}
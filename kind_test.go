@@ -0,0 +1,70 @@
+package srcset
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_ImageSource_Kind(t *testing.T) {
+	tests := []struct {
+		name string
+		src  ImageSource
+		want DescriptorKind
+	}{
+		{name: "Default", src: ImageSource{URL: "a.png"}, want: KindDefault},
+		{name: "Width", src: ImageSource{URL: "a.png", Width: i(320)}, want: KindWidth},
+		{name: "Width and height", src: ImageSource{URL: "a.png", Width: i(320), Height: i(200)}, want: KindWidth},
+		{name: "Density", src: ImageSource{URL: "a.png", Density: fl(2)}, want: KindDensity},
+		{name: "Width and density", src: ImageSource{URL: "a.png", Width: i(320), Density: fl(2)}, want: KindMixed},
+		{name: "Height only", src: ImageSource{URL: "a.png", Height: i(200)}, want: KindMixed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.src.Kind(); got != tt.want {
+				t.Errorf("%q. Kind() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SourceSet_Kind(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		want           DescriptorKind
+		wantWidthBased bool
+		wantDensity    bool
+	}{
+		{name: "Empty", input: "", want: KindDefault, wantDensity: true},
+		{name: "Default", input: "a.png", want: KindDefault, wantDensity: true},
+		{name: "Widths", input: "a.png 320w, b.png 640w 480h", want: KindWidth, wantWidthBased: true},
+		{name: "Densities", input: "a.png, b.png 2x", want: KindDensity, wantDensity: true},
+		{name: "Densities first", input: "a.png 2x, b.png", want: KindDensity, wantDensity: true},
+		{name: "Widths and densities", input: "a.png 320w, b.png 2x", want: KindMixed},
+		{name: "Widths and default", input: "a.png, b.png 320w", want: KindMixed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.input).Kind()
+			if got != tt.want || got.IsWidthBased() != tt.wantWidthBased || got.IsDensityBased() != tt.wantDensity {
+				t.Errorf("%q. Kind() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got := (SourceSet{{URL: "a.png", Width: i(1), Density: fl(1)}}).Kind(); got != KindMixed {
+		t.Errorf("Kind() = %v, want %v", got, KindMixed)
+	}
+	if got := DescriptorKind(9).String(); got != "DescriptorKind(9)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func Test_DescriptorKind_MarshalText(t *testing.T) {
+	got, err := json.Marshal(struct{ Kind DescriptorKind }{KindWidth})
+	if want := `{"Kind":"width"}`; err != nil || string(got) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", got, err, want)
+	}
+}
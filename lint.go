@@ -0,0 +1,40 @@
+package srcset
+
+import "strings"
+
+// Lint returns a violation for every candidate that follows a practice that
+// is valid, but likely to cause problems:
+//
+//   - Protocol-relative URLs, such as "//cdn.example.com/a.png", break in
+//     contexts that are not served over HTTP, such as email or files. See
+//     ResolveProtocolRelative.
+func (s SourceSet) Lint() []Violation {
+	var violations []Violation
+	for _, src := range s {
+		if isProtocolRelative(src.URL) {
+			violations = append(violations, Violation{
+				Offset:  src.Offset,
+				URL:     src.URL,
+				Message: "protocol-relative URL",
+				Err:     ErrProtocolRelative,
+			})
+		}
+	}
+	return violations
+}
+
+// ResolveProtocolRelative returns a copy of s in which protocol-relative
+// URLs are given the scheme, such as "https".
+func (s SourceSet) ResolveProtocolRelative(scheme string) SourceSet {
+	resolved := s.Clone()
+	for i, src := range resolved {
+		if isProtocolRelative(src.URL) {
+			resolved[i].URL = scheme + ":" + src.URL
+		}
+	}
+	return resolved
+}
+
+func isProtocolRelative(url string) bool {
+	return strings.HasPrefix(url, "//")
+}
@@ -0,0 +1,40 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Lint(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Violation
+	}{
+		{
+			name:  "No findings",
+			input: "a.png 1x, https://cdn.example.com/b.png 2x",
+		},
+		{
+			name:  "Protocol-relative URL",
+			input: "a.png 1x, //cdn.example.com/b.png 2x",
+			want:  []Violation{{Offset: 10, URL: "//cdn.example.com/b.png", Message: "protocol-relative URL", Err: ErrProtocolRelative}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).Lint(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Lint() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ResolveProtocolRelative(t *testing.T) {
+	set := Parse("/a.png 1x, //cdn.example.com/b.png 2x, http://example.com/c.png 3x")
+	want := "/a.png 1x, https://cdn.example.com/b.png 2x, http://example.com/c.png 3x"
+	if got := set.ResolveProtocolRelative("https").String(); got != want {
+		t.Errorf("ResolveProtocolRelative() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,287 @@
+package srcset
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// mediaCondition is a parsed media condition, as used in the sizes
+// attribute.
+type mediaCondition interface {
+	matches(ctx EvalContext) bool
+}
+
+type mediaNot struct {
+	cond mediaCondition
+}
+
+func (m mediaNot) matches(ctx EvalContext) bool {
+	return !m.cond.matches(ctx)
+}
+
+type mediaAnd []mediaCondition
+
+func (m mediaAnd) matches(ctx EvalContext) bool {
+	for _, c := range m {
+		if !c.matches(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+type mediaOr []mediaCondition
+
+func (m mediaOr) matches(ctx EvalContext) bool {
+	for _, c := range m {
+		if c.matches(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaRange compares the viewport width against a length. A nil length is
+// a boolean feature, which matches any non-zero width.
+type mediaRange struct {
+	op     string
+	length *length
+}
+
+func (m mediaRange) matches(ctx EvalContext) bool {
+	viewportWidth := ctx.ViewportWidth
+	if m.length == nil {
+		return viewportWidth != 0
+	}
+
+	v := m.length.px(ctx)
+	switch m.op {
+	case "<":
+		return viewportWidth < v
+	case "<=":
+		return viewportWidth <= v
+	case ">":
+		return viewportWidth > v
+	case ">=":
+		return viewportWidth >= v
+	default:
+		return viewportWidth == v
+	}
+}
+
+var errInvalidMedia = errors.New("invalid media condition")
+
+// parseMediaCondition parses a media condition without media type, as
+// defined in Media Queries Level 4. Only the width feature is supported.
+func parseMediaCondition(input string) (mediaCondition, error) {
+	p := &mediaParser{toks: tokenizeMedia(strings.ToLower(input))}
+	cond, err := p.condition()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, errInvalidMedia
+	}
+	return cond, nil
+}
+
+func tokenizeMedia(input string) []string {
+	var (
+		toks []string
+		word strings.Builder
+	)
+
+	flush := func() {
+		if word.Len() > 0 {
+			toks = append(toks, word.String())
+			word.Reset()
+		}
+	}
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case isSpace(rune(c)):
+			flush()
+		case c == '(' || c == ')' || c == ':' || c == '=':
+			flush()
+			toks = append(toks, string(c))
+		case c == '<' || c == '>':
+			flush()
+			if i+1 < len(input) && input[i+1] == '=' {
+				toks = append(toks, input[i:i+2])
+				i++
+			} else {
+				toks = append(toks, string(c))
+			}
+		default:
+			word.WriteByte(c)
+		}
+	}
+	flush()
+
+	return toks
+}
+
+type mediaParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *mediaParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *mediaParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *mediaParser) condition() (mediaCondition, error) {
+	if p.peek() == "not" {
+		p.next()
+		cond, err := p.inParens()
+		if err != nil {
+			return nil, err
+		}
+		return mediaNot{cond}, nil
+	}
+
+	first, err := p.inParens()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	if op != "and" && op != "or" {
+		return first, nil
+	}
+
+	conds := []mediaCondition{first}
+	for p.peek() == op {
+		p.next()
+		cond, err := p.inParens()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	if p.peek() == "and" || p.peek() == "or" {
+		// Mixing "and" and "or" without parentheses is invalid.
+		return nil, errInvalidMedia
+	}
+
+	if op == "and" {
+		return mediaAnd(conds), nil
+	}
+	return mediaOr(conds), nil
+}
+
+func (p *mediaParser) inParens() (mediaCondition, error) {
+	if p.next() != "(" {
+		return nil, errInvalidMedia
+	}
+
+	if tok := p.peek(); tok == "(" || tok == "not" {
+		cond, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errInvalidMedia
+		}
+		return cond, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.toks) && p.toks[p.pos] != ")" {
+		if p.toks[p.pos] == "(" {
+			return nil, errInvalidMedia
+		}
+		p.pos++
+	}
+	if p.pos == len(p.toks) {
+		return nil, errInvalidMedia
+	}
+	feature := p.toks[start:p.pos]
+	p.next()
+
+	return parseMediaFeature(feature)
+}
+
+func parseMediaFeature(toks []string) (mediaCondition, error) {
+	switch {
+	case len(toks) == 1 && toks[0] == "width":
+		return mediaRange{}, nil
+
+	case len(toks) == 3 && toks[1] == ":":
+		l, err := parseLength(toks[2])
+		if err != nil {
+			return nil, err
+		}
+		switch toks[0] {
+		case "width":
+			return mediaRange{op: "=", length: &l}, nil
+		case "min-width":
+			return mediaRange{op: ">=", length: &l}, nil
+		case "max-width":
+			return mediaRange{op: "<=", length: &l}, nil
+		}
+
+	case len(toks) == 3 && isRangeOp(toks[1]):
+		if toks[0] == "width" {
+			l, err := parseLength(toks[2])
+			if err != nil {
+				return nil, err
+			}
+			return mediaRange{op: toks[1], length: &l}, nil
+		}
+		if toks[2] == "width" {
+			l, err := parseLength(toks[0])
+			if err != nil {
+				return nil, err
+			}
+			return mediaRange{op: flipRangeOp(toks[1]), length: &l}, nil
+		}
+
+	case len(toks) == 5 && toks[2] == "width" && isRangeOp(toks[1]) && isRangeOp(toks[3]):
+		lower, err := parseMediaFeature(toks[:3])
+		if err != nil {
+			return nil, err
+		}
+		upper, err := parseMediaFeature(toks[2:])
+		if err != nil {
+			return nil, err
+		}
+		return mediaAnd{lower, upper}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported media feature %q", strings.Join(toks, " "))
+}
+
+func isRangeOp(tok string) bool {
+	switch tok {
+	case "<", "<=", ">", ">=", "=":
+		return true
+	}
+	return false
+}
+
+func flipRangeOp(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	}
+	return op
+}
@@ -0,0 +1,42 @@
+package srcset
+
+// MergeStrategy resolves a conflict between two candidates with the same
+// descriptors, returning the candidate to keep.
+type MergeStrategy func(existing, incoming ImageSource) ImageSource
+
+var (
+	// KeepFirst keeps the candidate that was seen first.
+	KeepFirst MergeStrategy = func(existing, _ ImageSource) ImageSource { return existing }
+	// KeepLast keeps the candidate that was seen last.
+	KeepLast MergeStrategy = func(_, incoming ImageSource) ImageSource { return incoming }
+)
+
+// Merge combines the candidates of sets into a single SourceSet. When several
+// candidates have the same descriptors, the first one is kept.
+func Merge(sets ...SourceSet) SourceSet {
+	return MergeWith(KeepFirst, sets...)
+}
+
+// MergeWith combines the candidates of sets into a single SourceSet, using
+// strategy to resolve candidates with the same descriptors. The resolved
+// candidate takes the position of the first conflicting candidate.
+func MergeWith(strategy MergeStrategy, sets ...SourceSet) SourceSet {
+	var (
+		merged = SourceSet{}
+		index  = map[string]int{}
+	)
+
+	for _, set := range sets {
+		for _, src := range set {
+			key := descriptorKey(src)
+			if i, ok := index[key]; ok {
+				merged[i] = strategy(merged[i], src)
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, src)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,54 @@
+package srcset
+
+import "testing"
+
+func Test_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		sets     []string
+		want     string
+	}{
+		{
+			name:     "No sets",
+			strategy: KeepFirst,
+			want:     "",
+		},
+		{
+			name:     "Disjoint",
+			strategy: KeepFirst,
+			sets:     []string{"a.png 1x", "b.png 2x"},
+			want:     "a.png 1x, b.png 2x",
+		},
+		{
+			name:     "Keep first",
+			strategy: KeepFirst,
+			sets:     []string{"a.png 320w, b.png 640w", "c.png 640w, d.png 1280w"},
+			want:     "a.png 320w, b.png 640w, d.png 1280w",
+		},
+		{
+			name:     "Keep last",
+			strategy: KeepLast,
+			sets:     []string{"a.png 320w, b.png 640w", "c.png 640w, d.png 1280w"},
+			want:     "a.png 320w, c.png 640w, d.png 1280w",
+		},
+		{
+			name:     "Duplicate within a set",
+			strategy: KeepFirst,
+			sets:     []string{"a.png, b.png 1x"},
+			want:     "a.png, b.png 1x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sets []SourceSet
+			for _, s := range tt.sets {
+				sets = append(sets, Parse(s))
+			}
+			if got := MergeWith(tt.strategy, sets...).String(); got != tt.want {
+				t.Errorf("%q. MergeWith() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
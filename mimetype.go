@@ -0,0 +1,57 @@
+package srcset
+
+import (
+	"path"
+	"strings"
+)
+
+// imageTypes maps lowercase file extensions to image MIME types.
+var imageTypes = map[string]string{
+	".apng": "image/apng",
+	".avif": "image/avif",
+	".bmp":  "image/bmp",
+	".gif":  "image/gif",
+	".heic": "image/heic",
+	".heif": "image/heif",
+	".ico":  "image/x-icon",
+	".jfif": "image/jpeg",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".jxl":  "image/jxl",
+	".png":  "image/png",
+	".svg":  "image/svg+xml",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".webp": "image/webp",
+}
+
+// GuessType infers the MIME type of the image from the media type of a data
+// URL, or from the file extension of any other URL, such as "image/avif". It
+// returns an empty string if the type cannot be inferred.
+func (src ImageSource) GuessType() string {
+	url := src.URL
+	if len(url) >= 5 && strings.EqualFold(url[:5], "data:") {
+		mediaType := url[5:]
+		if i := strings.IndexAny(mediaType, ";,"); i >= 0 {
+			mediaType = mediaType[:i]
+		}
+		return strings.ToLower(strings.TrimSpace(mediaType))
+	}
+
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	return imageTypes[strings.ToLower(path.Ext(url))]
+}
+
+// GroupByType groups the candidates by the MIME type inferred by GuessType,
+// preserving their order. Candidates of unknown type are grouped under the
+// empty string.
+func (s SourceSet) GroupByType() map[string]SourceSet {
+	groups := map[string]SourceSet{}
+	for _, src := range s {
+		typ := src.GuessType()
+		groups[typ] = append(groups[typ], src)
+	}
+	return groups
+}
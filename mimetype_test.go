@@ -0,0 +1,49 @@
+package srcset
+
+import "testing"
+
+func Test_GuessType(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "Relative URL", url: "a.avif", want: "image/avif"},
+		{name: "Absolute URL", url: "https://example.com/img/a.webp", want: "image/webp"},
+		{name: "Uppercase extension", url: "A.JPG", want: "image/jpeg"},
+		{name: "Query and fragment", url: "/a.png?v=1.gif#x.svg", want: "image/png"},
+		{name: "Dot in directory", url: "/img.d/a", want: ""},
+		{name: "Unknown extension", url: "a.php?id=1", want: ""},
+		{name: "No extension", url: "https://example.com/", want: ""},
+		{name: "Data URL", url: "data:image/svg+xml;charset=utf-8,<svg/>", want: "image/svg+xml"},
+		{name: "Data URL uppercase", url: "DATA:Image/PNG;base64,AAAA", want: "image/png"},
+		{name: "Data URL without type", url: "data:,abc", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ImageSource{URL: tt.url}).GuessType(); got != tt.want {
+				t.Errorf("%q. GuessType() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GroupByType(t *testing.T) {
+	set := Parse("a.avif 1x, a.jpg 1x, b.avif 2x, a.cgi 1x, b.JPEG 2x")
+	want := map[string]string{
+		"image/avif": "a.avif 1x, b.avif 2x",
+		"image/jpeg": "a.jpg 1x, b.JPEG 2x",
+		"":           "a.cgi 1x",
+	}
+
+	got := set.GroupByType()
+	if len(got) != len(want) {
+		t.Errorf("GroupByType() has %d groups, want %d", len(got), len(want))
+	}
+	for typ, w := range want {
+		if g := got[typ].String(); g != w {
+			t.Errorf("GroupByType()[%q] = %q, want %q", typ, g, w)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package srcset
+
+// Minify returns the shortest srcset attribute value that is equivalent to
+// s: duplicate candidates are removed as Normalize does, an explicit 1x
+// descriptor is dropped, and no whitespace is emitted where it is not needed.
+func (s SourceSet) Minify() string {
+	var (
+		dst    []byte
+		spaced bool // whether the previous candidate needs a space after the comma
+	)
+
+	for _, src := range s.Normalize() {
+		c := src.Candidate()
+		if len(dst) > 0 {
+			dst = append(dst, ',')
+			if spaced {
+				dst = append(dst, ' ')
+			}
+		}
+
+		if c.HasDensity && c.Density == 1 && !c.HasWidth && !c.HasHeight &&
+			len(src.Extensions) == 0 && len(src.RawDescriptors) == 0 {
+			src.Density = nil
+		}
+
+		dst = src.AppendTo(dst)
+		// A URL directly followed by a comma would swallow the comma.
+		spaced = src.Width == nil && src.Height == nil && src.Density == nil &&
+			len(src.Extensions) == 0 && len(src.RawDescriptors) == 0
+	}
+
+	return string(dst)
+}
@@ -0,0 +1,28 @@
+package srcset
+
+import "testing"
+
+func Test_Minify(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Empty", input: "", want: ""},
+		{name: "Whitespace", input: "  a.png   1x ,\n\tb.png  2x  ", want: "a.png, b.png 2x"},
+		{name: "No descriptors", input: "a.png, b.png 2x", want: "a.png, b.png 2x"},
+		{name: "Explicit 1x", input: "a.png 2x, b.png 1x", want: "a.png 2x,b.png"},
+		{name: "Duplicate density", input: "a.png, b.png 1x, c.png 2x, d.png 2.0x", want: "a.png, c.png 2x"},
+		{name: "Duplicate width", input: "a.png 320w, b.png 320w 200h, c.png 640w", want: "a.png 320w,c.png 640w"},
+		{name: "Trailing number formatting", input: "a.png 1.50x", want: "a.png 1.5x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.input).Minify()
+			if got != tt.want {
+				t.Errorf("%q. Minify() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
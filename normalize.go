@@ -0,0 +1,63 @@
+package srcset
+
+import "net/url"
+
+// Normalize returns s without candidates whose descriptor duplicates that of
+// an earlier candidate, as browsers ignore all but the first candidate for
+// each width and density. Candidates without descriptors count as 1x.
+func (s SourceSet) Normalize() SourceSet {
+	var (
+		normalized = make(SourceSet, 0, len(s))
+		widths     = map[int64]bool{}
+		densities  = map[float64]bool{}
+	)
+
+	for _, src := range s {
+		c := src.Candidate()
+		if c.HasWidth {
+			if widths[c.Width] {
+				continue
+			}
+			widths[c.Width] = true
+		} else {
+			if densities[c.density()] {
+				continue
+			}
+			densities[c.density()] = true
+		}
+		normalized = append(normalized, src)
+	}
+
+	return normalized
+}
+
+// DedupeURLs returns s without candidates that point at the same resource as
+// an earlier candidate, after resolving their URLs against base and
+// canonicalizing them as Canonicalize does with HostASCII. The remaining
+// candidates keep their URLs as they are. An error is returned if base is
+// not a valid URL; if it is empty, URLs are only canonicalized.
+func (s SourceSet) DedupeURLs(base string) (SourceSet, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		deduped = make(SourceSet, 0, len(s))
+		seen    = map[string]bool{}
+	)
+	for _, src := range s {
+		resolved := src.URL
+		if u, err := url.Parse(src.URL); err == nil && base != "" {
+			resolved = b.ResolveReference(u).String()
+		}
+		key := canonicalURL(resolved, HostASCII)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, src)
+	}
+
+	return deduped, nil
+}
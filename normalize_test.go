@@ -0,0 +1,82 @@
+package srcset
+
+import "testing"
+
+func Test_Normalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "No duplicates", input: "a.png 1x, b.png 2x", want: "a.png 1x, b.png 2x"},
+		{name: "Duplicate density", input: "a.png 2x, b.png 1x, c.png 2x", want: "a.png 2x, b.png 1x"},
+		{name: "Implicit 1x", input: "a.png, b.png 1x, c.png 1.0x", want: "a.png"},
+		{name: "Duplicate width", input: "a.png 320w, b.png 640w, c.png 320w 200h", want: "a.png 320w, b.png 640w"},
+		{name: "Empty", input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).Normalize().String(); got != tt.want {
+				t.Errorf("%q. Normalize() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Normalize_selection(t *testing.T) {
+	set := Parse("a.png 2x, b.png 1x, c.png 2x")
+	for _, dpr := range []float64{1, 2, 3} {
+		got, _ := set.BestForDPR(dpr)
+		want, _ := set.Normalize().BestForDPR(dpr)
+		if got.URL != want.URL {
+			t.Errorf("BestForDPR(%v) = %q, want %q after Normalize", dpr, got.URL, want.URL)
+		}
+	}
+}
+
+func Test_DedupeURLs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		base    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Relative spellings",
+			input: "img/a.png 1x, ./img/a.png 2x, /blog/img/a.png 3x, https://EXAMPLE.com:443/blog/img/../img/a.png 4x, img/b.png 2x",
+			base:  "https://example.com/blog/post.html",
+			want:  "img/a.png 1x, img/b.png 2x",
+		},
+		{
+			name:  "Internationalized host",
+			input: "https://bücher.de/a.png 1x, //xn--bcher-kva.de/a.png 2x",
+			base:  "https://example.com/",
+			want:  "https://bücher.de/a.png 1x",
+		},
+		{
+			name:  "Without base",
+			input: "a.png 1x, /b/../a.png 2x, /a.png 3x",
+			want:  "a.png 1x, /b/../a.png 2x",
+		},
+		{
+			name:    "Invalid base",
+			input:   "a.png",
+			base:    "http://[::1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input).DedupeURLs(tt.base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. DedupeURLs() error = %v, want error %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("%q. DedupeURLs() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
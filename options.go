@@ -0,0 +1,181 @@
+package srcset
+
+// Option configures the behaviour of Parse and Parser.
+type Option func(*config)
+
+type config struct {
+	warn               func(Warning)
+	maxCandidates      int
+	descriptorHandlers map[byte]DescriptorHandler
+	forwardCompat      bool
+	profile            SpecProfile
+	allowedSchemes     []string
+	decodeEntities     bool
+	attrNames          *AttributeNames
+	stats              StatsRecorder
+	log                func(Warning)
+	arena              *Arena
+	lenientCase        bool
+	lenientSpace       bool
+	failFast           bool
+	stopped            bool // set by parseStrict to abort parsing
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithWarningHandler registers fn to be called for every problem the parser
+// recovers from, such as dropped candidates or skipped garbage. A Parser
+// shared between goroutines, or ParseAll, calls fn concurrently, in which
+// case fn must be safe for concurrent use.
+func WithWarningHandler(fn func(Warning)) Option {
+	return func(c *config) {
+		c.warn = fn
+	}
+}
+
+// WarningHandlerOf returns the warning handler registered by opts with
+// WithWarningHandler, or nil if there is none. Packages that add their own
+// handler to options passed in by a caller use it to call the caller's
+// handler as well.
+func WarningHandlerOf(opts ...Option) func(Warning) {
+	return newConfig(opts).warn
+}
+
+// WithMaxCandidates stops parsing once n candidates have been collected. The
+// remainder of the input is reported as a dropped candidate. A value of zero or
+// less means no limit.
+func WithMaxCandidates(n int) Option {
+	return func(c *config) {
+		c.maxCandidates = n
+	}
+}
+
+// DescriptorHandler validates the value of a custom descriptor, which is the
+// descriptor without its suffix, and returns the value to store in the
+// Extensions of the candidate. Returning an error invalidates the candidate.
+type DescriptorHandler func(value string) (string, error)
+
+// WithDescriptorHandler makes the parser accept descriptors ending in suffix,
+// such as "80q" for the suffix 'q', by passing them to fn instead of
+// invalidating the candidate. The results are stored in the Extensions of the
+// candidate under the suffix. A candidate may have at most one descriptor
+// per suffix. Handlers for the standard suffixes 'w', 'x' and 'h' are only
+// consulted for descriptors that are not valid standard descriptors.
+func WithDescriptorHandler(suffix byte, fn DescriptorHandler) Option {
+	return func(c *config) {
+		if c.descriptorHandlers == nil {
+			c.descriptorHandlers = map[byte]DescriptorHandler{}
+		}
+		c.descriptorHandlers[suffix] = fn
+	}
+}
+
+// WithForwardCompatibility preserves descriptors of unknown types in the
+// RawDescriptors of the candidate, instead of dropping the candidate. This
+// keeps tooling working when new descriptor types are added to the platform.
+// Invalid width, density and height descriptors still invalidate the
+// candidate.
+func WithForwardCompatibility() Option {
+	return func(c *config) {
+		c.forwardCompat = true
+	}
+}
+
+// SpecProfile selects the version of the specification the parser follows
+// where versions differ.
+type SpecProfile int
+
+const (
+	// HTML5 follows the parsing rules of the HTML5 era, where a height
+	// descriptor is accepted on its own. This is the default.
+	HTML5 SpecProfile = iota
+	// Living follows the WHATWG HTML living standard, where a height
+	// descriptor is only accepted together with a width descriptor.
+	Living
+)
+
+// WithSpecProfile makes the parser follow the given version of the
+// specification.
+func WithSpecProfile(profile SpecProfile) Option {
+	return func(c *config) {
+		c.profile = profile
+	}
+}
+
+// WithEntityDecoding decodes HTML character references such as "&amp;" in
+// the input before parsing it, for attribute values taken from raw HTML.
+// Offsets in the results refer to the decoded input.
+func WithEntityDecoding() Option {
+	return func(c *config) {
+		c.decodeEntities = true
+	}
+}
+
+// WithAttributeNames sets the names of the HTML attributes that are
+// recognized when extracting or rewriting responsive images. It defaults to
+// DefaultAttributeNames.
+func WithAttributeNames(names AttributeNames) Option {
+	return func(c *config) {
+		c.attrNames = &names
+	}
+}
+
+// WithLenientDescriptorCase makes the parser accept descriptors with an
+// uppercase suffix, such as "2X" or "480W", which browsers reject, as if they
+// were lowercase. Each is reported as a Fixable warning.
+func WithLenientDescriptorCase() Option {
+	return func(c *config) {
+		c.lenientCase = true
+	}
+}
+
+// WithLenientWhitespace makes the parser treat whitespace outside ASCII, such
+// as no-break spaces in text pasted from word processors, and zero width
+// spaces as separators, like ASCII whitespace. Each run of such characters is
+// reported as a Fixable warning. Without this option, they are part of URLs
+// and descriptors, as the spec requires.
+func WithLenientWhitespace() Option {
+	return func(c *config) {
+		c.lenientSpace = true
+	}
+}
+
+// WithFailFast makes ParseStrict stop at the first problem, and return the
+// candidates parsed up to it with a *ParseError listing only that problem.
+// Fixable warnings are not problems, so parsing continues after them. It has
+// no effect on Parse.
+func WithFailFast() Option {
+	return func(c *config) {
+		c.failFast = true
+	}
+}
+
+func (c *config) report(at *Positioner, kind WarningKind, offset int, text, message string, err error) {
+	c.emit(at, Warning{Kind: kind, Offset: offset, Text: text, Message: message, Err: err})
+}
+
+// reportFix reports a Fixable warning, where fix replaces text.
+func (c *config) reportFix(at *Positioner, offset int, text, fix, message string, err error) {
+	c.emit(at, Warning{Kind: Fixable, Offset: offset, Text: text, Fix: fix, Message: message, Err: err})
+}
+
+// emit passes w to the warning handlers, after filling in its position.
+func (c *config) emit(at *Positioner, w Warning) {
+	if c.warn == nil && c.log == nil {
+		return
+	}
+	pos := at.PositionOf(w.Offset)
+	w.Line, w.Column = pos.Line, pos.Column
+	if c.warn != nil {
+		c.warn(w)
+	}
+	if c.log != nil {
+		c.log(w)
+	}
+}
@@ -0,0 +1,230 @@
+package srcset
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func quality(value string) (string, error) {
+	if q, err := strconv.Atoi(value); err != nil || q < 1 || q > 100 {
+		return "", errors.New("invalid quality")
+	}
+	return value, nil
+}
+
+func Test_options(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  SourceSet
+	}{
+		{
+			name:  "No options",
+			input: "a.png 1x, b.png 2x",
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 10},
+			},
+		},
+		{
+			name:  "Max candidates",
+			input: "a.png 1x, b.png 2x, c.png 3x",
+			opts:  []Option{WithMaxCandidates(2)},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 10},
+			},
+		},
+		{
+			name:  "Unknown descriptor without handler",
+			input: "a.png 1x 80q, b.png 2x",
+			want: SourceSet{
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 14},
+			},
+		},
+		{
+			name:  "Descriptor handler",
+			input: "a.png 1x 80q, b.png 2x, c.png 3x 0q",
+			opts:  []Option{WithDescriptorHandler('q', quality)},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0, Extensions: map[string]string{"q": "80"}},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 14},
+			},
+		},
+		{
+			name:  "Duplicate custom descriptor",
+			input: "a.png 80q 90q",
+			opts:  []Option{WithDescriptorHandler('q', quality)},
+			want:  SourceSet{},
+		},
+		{
+			name:  "Forward compatibility",
+			input: "a.png 1x 80q future(1), b.png 2x 1W, c.png 3x 4x",
+			opts:  []Option{WithForwardCompatibility()},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0, RawDescriptors: []string{"80q", "future(1)"}},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 24, RawDescriptors: []string{"1W"}},
+			},
+		},
+		{
+			name:  "Forward compatibility with invalid width",
+			input: "a.png 1.5w",
+			opts:  []Option{WithForwardCompatibility()},
+			want:  SourceSet{},
+		},
+		{
+			name:  "HTML5 profile",
+			input: "a.png 100h, b.png 100w 100h",
+			opts:  []Option{WithSpecProfile(HTML5)},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Height: i(100), Offset: 0},
+				ImageSource{URL: "b.png", Width: i(100), Height: i(100), Offset: 12},
+			},
+		},
+		{
+			name:  "Living profile",
+			input: "a.png 100h, b.png 100w 100h",
+			opts:  []Option{WithSpecProfile(Living)},
+			want: SourceSet{
+				ImageSource{URL: "b.png", Width: i(100), Height: i(100), Offset: 12},
+			},
+		},
+		{
+			name:  "Without entity decoding",
+			input: "a.png?w=1&amp;h=2 1x",
+			want: SourceSet{
+				ImageSource{URL: "a.png?w=1&amp;h=2", Density: fl(1), Offset: 0},
+			},
+		},
+		{
+			name:  "Entity decoding",
+			input: "a.png?w=1&amp;h=2 1x&#44; b.png&#x20;2x",
+			opts:  []Option{WithEntityDecoding()},
+			want: SourceSet{
+				ImageSource{URL: "a.png?w=1&h=2", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 18},
+			},
+		},
+		{
+			name:  "Uppercase descriptors",
+			input: "a.png 1X, b.png 480W 320H",
+			want:  SourceSet{},
+		},
+		{
+			name:  "Lenient descriptor case",
+			input: "a.png 1X, b.png 480W 320H",
+			opts:  []Option{WithLenientDescriptorCase()},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Width: i(480), Height: i(320), Offset: 10},
+			},
+		},
+		{
+			name:  "Unicode whitespace",
+			input: "a.png\u00a02x, b.png 1x",
+			want: SourceSet{
+				ImageSource{URL: "a.png\u00a02x", Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(1), Offset: 11},
+			},
+		},
+		{
+			name:  "Lenient whitespace",
+			input: "a.png\u00a02x,\u2003b.png\u200b\u3000640w\u202f480h",
+			opts:  []Option{WithLenientWhitespace()},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(2), Offset: 0},
+				ImageSource{URL: "b.png", Width: i(640), Height: i(480), Offset: 13},
+			},
+		},
+		{
+			name:  "Max candidates not reached",
+			input: "a.png 1x",
+			opts:  []Option{WithMaxCandidates(2)},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input, tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Parse() = %v, want %v", tt.name, got, tt.want)
+			}
+			if got := NewParser(tt.opts...).Parse(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Parser.Parse() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithLenientDescriptorCase_warnings(t *testing.T) {
+	var got []Warning
+	Parse("a.png 2x 2X, b.png  480W", WithLenientDescriptorCase(), WithWarningHandler(func(w Warning) {
+		got = append(got, w)
+	}))
+
+	want := []Warning{
+		{Kind: Fixable, Offset: 9, Line: 1, Column: 10, Text: "2X", Fix: "2x", Message: "uppercase descriptor suffix", Err: ErrInvalidDescriptor},
+		{Kind: DroppedCandidate, Offset: 0, Line: 1, Column: 1, Text: "a.png 2x 2X", Message: "invalid descriptors", Err: ErrDuplicateDescriptor},
+		{Kind: Fixable, Offset: 20, Line: 1, Column: 21, Text: "480W", Fix: "480w", Message: "uppercase descriptor suffix", Err: ErrInvalidDescriptor},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+}
+
+func Test_WithLenientDescriptorCase_strict(t *testing.T) {
+	// Fixable warnings are passed to the handler, but are not errors.
+	var warnings int
+	got, err := ParseStrict("a.png 2X", WithLenientDescriptorCase(), WithWarningHandler(func(Warning) {
+		warnings++
+	}))
+	if err != nil {
+		t.Fatalf("ParseStrict() error = %v", err)
+	}
+	if want := "a.png 2x"; got.String() != want {
+		t.Errorf("ParseStrict() = %q, want %q", got, want)
+	}
+	if warnings != 1 {
+		t.Errorf("warnings = %d, want 1", warnings)
+	}
+
+	if _, err := NewParser(WithLenientWhitespace()).ParseStrict("a.png\u00a02x"); err != nil {
+		t.Errorf("Parser.ParseStrict() error = %v", err)
+	}
+}
+
+func Test_WithLenientWhitespace_warnings(t *testing.T) {
+	var got []Warning
+	Parse("a.png\u00a0\u00a02x,  b.png 1x\u3000", WithLenientWhitespace(), WithWarningHandler(func(w Warning) {
+		got = append(got, w)
+	}))
+
+	want := []Warning{
+		{Kind: Fixable, Offset: 5, Line: 1, Column: 6, Text: "\u00a0\u00a0", Fix: " ", Message: "non-ASCII whitespace", Err: ErrSyntax},
+		{Kind: Fixable, Offset: 22, Line: 1, Column: 21, Text: "\u3000", Fix: " ", Message: "non-ASCII whitespace", Err: ErrSyntax},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+}
+
+func Test_WarningHandlerOf(t *testing.T) {
+	if WarningHandlerOf(WithMaxCandidates(1)) != nil {
+		t.Error("WarningHandlerOf() without a handler != nil")
+	}
+
+	var called bool
+	fn := WarningHandlerOf(WithWarningHandler(func(Warning) { called = true }), WithMaxCandidates(1))
+	if fn == nil {
+		t.Fatal("WarningHandlerOf() = nil, want the handler")
+	}
+	fn(Warning{})
+	if !called {
+		t.Error("WarningHandlerOf() returned another handler")
+	}
+}
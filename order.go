@@ -0,0 +1,28 @@
+package srcset
+
+import "sort"
+
+// Sorted returns a copy of s in canonical order: width candidates by
+// ascending width, then height, followed by the other candidates by
+// ascending density, where candidates without descriptors count as 1x.
+// Candidates that compare equal are ordered by URL, and otherwise keep their
+// order, so the result does not depend on the order of s unless candidates
+// differ only in custom descriptors.
+func (s SourceSet) Sorted() SourceSet {
+	sorted := append(SourceSet(nil), s...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Candidate(), sorted[j].Candidate()
+		switch {
+		case a.HasWidth != b.HasWidth:
+			return a.HasWidth
+		case a.HasWidth && a.Width != b.Width:
+			return a.Width < b.Width
+		case a.HasWidth && a.Height != b.Height:
+			return a.Height < b.Height
+		case !a.HasWidth && a.density() != b.density():
+			return a.density() < b.density()
+		}
+		return a.URL < b.URL
+	})
+	return sorted
+}
@@ -0,0 +1,31 @@
+package srcset
+
+import "testing"
+
+func Test_Sorted(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Empty", input: "", want: ""},
+		{name: "Widths", input: "c.png 1280w, a.png 320w, b.png 640w", want: "a.png 320w, b.png 640w, c.png 1280w"},
+		{name: "Heights", input: "b.png 640w 480h, a.png 640w 360h", want: "a.png 640w 360h, b.png 640w 480h"},
+		{name: "Densities", input: "c.png 3x, a.png, b.png 1.5x", want: "a.png, b.png 1.5x, c.png 3x"},
+		{name: "Mixed", input: "a.png 2x, b.png 640w, c.png", want: "b.png 640w, c.png, a.png 2x"},
+		{name: "Ties by URL", input: "b.png 640w, a.png 640w, d.png 1x, c.png", want: "a.png 640w, b.png 640w, c.png, d.png 1x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := Parse(tt.input)
+			before := set.String()
+			if got := set.Sorted().String(); got != tt.want {
+				t.Errorf("%q. Sorted() = %q, want %q", tt.name, got, tt.want)
+			}
+			if set.String() != before {
+				t.Errorf("%q. Sorted() modified the set", tt.name)
+			}
+		})
+	}
+}
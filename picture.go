@@ -0,0 +1,89 @@
+package srcset
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// typeRanks orders the types of picture sources: browsers use the first
+// source whose type they support, so the most efficient formats come first
+// and the most widely supported ones last. Other types rank in between.
+var typeRanks = map[string]int{
+	"image/avif": 0,
+	"image/jxl":  1,
+	"image/webp": 2,
+	"image/png":  4,
+	"image/gif":  5,
+	"image/jpeg": 6,
+}
+
+const otherTypeRank = 3
+
+// RenderPicture renders a picture element with a source element for each
+// MIME type in sources, and an img element as fallback. The most widely
+// supported type, such as image/jpeg, is used for the img element, and
+// opts.Sizes is propagated to every source.
+func RenderPicture(sources map[string]SourceSet, opts ImgOptions) (template.HTML, error) {
+	if len(sources) == 0 {
+		return "", errors.New("srcset: no sources")
+	}
+
+	types := sortedTypes(sources)
+	var sb strings.Builder
+	sb.WriteString("<picture>")
+	for _, typ := range types[:len(types)-1] {
+		set := sources[typ]
+		if len(set) == 0 {
+			continue
+		}
+		if opts.Sort {
+			set = set.Sorted()
+		}
+		if len(set.Widths()) > 0 && len(opts.Sizes) == 0 {
+			return "", fmt.Errorf("srcset: width descriptors of %s source require sizes", typ)
+		}
+		sb.WriteString("<source")
+		writeAttr(&sb, "type", typ)
+		sb.WriteString(` srcset="`)
+		sb.WriteString(set.AttrString())
+		sb.WriteByte('"')
+		writeAttr(&sb, "sizes", opts.Sizes.String())
+		sb.WriteByte('>')
+	}
+
+	img, err := RenderImg(sources[types[len(types)-1]], opts)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(string(img))
+	sb.WriteString("</picture>")
+
+	return template.HTML(sb.String()), nil
+}
+
+// sortedTypes returns the types of sources in the order RenderPicture
+// renders them, with the type of the fallback img element last.
+func sortedTypes(sources map[string]SourceSet) []string {
+	types := make([]string, 0, len(sources))
+	for typ := range sources {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		ri, rj := typeRank(types[i]), typeRank(types[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+func typeRank(typ string) int {
+	if rank, ok := typeRanks[typ]; ok {
+		return rank
+	}
+	return otherTypeRank
+}
@@ -0,0 +1,80 @@
+package srcset
+
+import (
+	"html/template"
+	"testing"
+)
+
+func Test_RenderPicture(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]SourceSet
+		opts    ImgOptions
+		want    template.HTML
+		wantErr bool
+	}{
+		{
+			name: "Ordered sources",
+			sources: map[string]SourceSet{
+				"image/jpeg": Parse("a.jpg 320w, b.jpg 640w"),
+				"image/webp": Parse("a.webp 320w, b.webp 640w"),
+				"image/avif": Parse("a.avif 320w, b.avif 640w"),
+			},
+			opts: ImgOptions{Sizes: ParseSizes("50vw"), Alt: "Hero"},
+			want: `<picture>` +
+				`<source type="image/avif" srcset="a.avif 320w, b.avif 640w" sizes="50vw">` +
+				`<source type="image/webp" srcset="a.webp 320w, b.webp 640w" sizes="50vw">` +
+				`<img src="b.jpg" srcset="a.jpg 320w, b.jpg 640w" sizes="50vw" alt="Hero">` +
+				`</picture>`,
+		},
+		{
+			name: "Sorted",
+			sources: map[string]SourceSet{
+				"image/jpeg": Parse("b.jpg 640w, a.jpg 320w"),
+				"image/webp": Parse("b.webp 640w, a.webp 320w"),
+			},
+			opts: ImgOptions{Sizes: ParseSizes("50vw"), Sort: true},
+			want: `<picture>` +
+				`<source type="image/webp" srcset="a.webp 320w, b.webp 640w" sizes="50vw">` +
+				`<img src="b.jpg" srcset="a.jpg 320w, b.jpg 640w" sizes="50vw" alt="">` +
+				`</picture>`,
+		},
+		{
+			name: "Unknown types before widely supported ones",
+			sources: map[string]SourceSet{
+				"image/png":     Parse("a.png 1x"),
+				"image/svg+xml": Parse("a.svg"),
+				"image/webp":    Parse("a.webp 1x, b.webp 2x"),
+			},
+			want: `<picture>` +
+				`<source type="image/webp" srcset="a.webp 1x, b.webp 2x">` +
+				`<source type="image/svg+xml" srcset="a.svg">` +
+				`<img src="a.png" srcset="a.png 1x" alt="">` +
+				`</picture>`,
+		},
+		{
+			name:    "No sources",
+			wantErr: true,
+		},
+		{
+			name: "Source with widths without sizes",
+			sources: map[string]SourceSet{
+				"image/jpeg": Parse("a.jpg"),
+				"image/webp": Parse("a.webp 320w"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPicture(tt.sources, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. RenderPicture() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("%q. RenderPicture() = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
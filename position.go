@@ -0,0 +1,76 @@
+package srcset
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Position is a line and column in an input, both starting at 1. Lines end
+// at a line feed, a carriage return, or both, and columns count characters
+// rather than bytes.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// PositionOf translates a byte offset in input, such as the Offset of a
+// Warning or Violation, into a line and column. Offsets beyond the end of
+// input are clamped to the end. To translate many offsets in the same input,
+// use a Positioner.
+func PositionOf(input string, offset int) Position {
+	return NewPositioner(input).PositionOf(offset)
+}
+
+// A Positioner translates byte offsets in an input into lines and columns,
+// like PositionOf. It resumes scanning at the previous offset, so that
+// translating increasing offsets takes linear time in total; a smaller
+// offset restarts the scan at the beginning of the input.
+type Positioner struct {
+	input  string
+	offset int
+	pos    Position
+}
+
+// NewPositioner returns a Positioner for input.
+func NewPositioner(input string) *Positioner {
+	return &Positioner{input: input, pos: Position{Line: 1, Column: 1}}
+}
+
+// PositionOf translates offset into a line and column. Offsets beyond the
+// end of the input are clamped to the end.
+func (p *Positioner) PositionOf(offset int) Position {
+	if offset > len(p.input) {
+		offset = len(p.input)
+	}
+	if offset < p.offset {
+		p.offset, p.pos = 0, Position{Line: 1, Column: 1}
+	}
+
+	input, pos, i := p.input, p.pos, p.offset
+	for i < offset {
+		switch input[i] {
+		case '\n':
+			// The line feed of a CRLF pair was counted at the carriage
+			// return.
+			if i == 0 || input[i-1] != '\r' {
+				pos.Line++
+				pos.Column = 1
+			}
+			i++
+		case '\r':
+			pos.Line++
+			pos.Column = 1
+			i++
+		default:
+			_, size := utf8.DecodeRuneInString(input[i:])
+			pos.Column++
+			i += size
+		}
+	}
+	p.offset, p.pos = i, pos
+	return pos
+}
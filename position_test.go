@@ -0,0 +1,41 @@
+package srcset
+
+import "testing"
+
+func Test_PositionOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		offset int
+		want   Position
+	}{
+		{name: "Start", input: "a.png 1x", offset: 0, want: Position{1, 1}},
+		{name: "First line", input: "a.png 1x", offset: 6, want: Position{1, 7}},
+		{name: "Second line", input: "a.png 1x,\n  b.png 2x", offset: 12, want: Position{2, 3}},
+		{name: "CRLF", input: "a.png 1x,\r\n\r\nb.png 2x", offset: 13, want: Position{3, 1}},
+		{name: "CR", input: "a.png 1x,\rb.png 2x", offset: 10, want: Position{2, 1}},
+		{name: "Multi-byte characters", input: "ä.png 1x, ö.png", offset: 11, want: Position{1, 11}},
+		{name: "Beyond end", input: "a\nb", offset: 10, want: Position{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PositionOf(tt.input, tt.offset); got != tt.want {
+				t.Errorf("%q. PositionOf() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Positioner(t *testing.T) {
+	const input = "a.png 1x,\r\nb.png 2x,\n  ä.png 3x"
+	p := NewPositioner(input)
+
+	// Increasing offsets resume the scan, including inside a CRLF pair, and
+	// a smaller offset restarts it.
+	for _, offset := range []int{0, 6, 10, 11, 14, 23, 24, 27, 35, 6, 40} {
+		if got, want := p.PositionOf(offset), PositionOf(input, offset); got != want {
+			t.Errorf("PositionOf(%d) = %v, want %v", offset, got, want)
+		}
+	}
+}
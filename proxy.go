@@ -0,0 +1,135 @@
+package srcset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RewriteResponse returns a function for the ModifyResponse field of an
+// httputil.ReverseProxy, which rewrites the URLs of every candidate in the
+// srcset and imagesrcset attributes of HTML responses through mapURL, for
+// example to serve images from a CDN. The body is rewritten as it is
+// streamed to the client.
+//
+// As the proxy forwards the Accept-Encoding of the client, gzip-compressed
+// responses are decompressed and sent to the client uncompressed. Responses
+// that are not HTML, or that have another Content-Encoding such as br, are
+// passed through unchanged. The options are passed to RewriteHTML.
+func RewriteResponse(mapURL func(url string) string, opts ...Option) func(*http.Response) error {
+	return func(resp *http.Response) error {
+		if !isRewritable(resp) {
+			return nil
+		}
+		if err := decodeBody(resp); err != nil {
+			return err
+		}
+
+		body := resp.Body
+		pr, pw := io.Pipe()
+		go func() {
+			err := RewriteHTML(body, pw, func(src ImageSource) ImageSource {
+				src.URL = mapURL(src.URL)
+				return src
+			}, opts...)
+			body.Close()
+			pw.CloseWithError(err)
+		}()
+
+		resp.Body = pr
+		resp.ContentLength = -1
+		resp.Header.Del("Content-Length")
+		return nil
+	}
+}
+
+// RewriteTransport returns an http.RoundTripper that sends requests through
+// base, or http.DefaultTransport if it is nil, and rewrites the URLs of every
+// candidate in HTML responses through mapURL, like RewriteResponse does for
+// a reverse proxy. As a client has to wait for the whole body anyway, the
+// body is rewritten before the response is returned, so that its
+// Content-Length is accurate.
+//
+// Gzip-compressed responses are decompressed. Responses to HEAD requests,
+// responses that are not HTML, and responses that have another
+// Content-Encoding are returned unchanged. The options are passed to
+// RewriteHTML.
+func RewriteTransport(base http.RoundTripper, mapURL func(url string) string, opts ...Option) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rewriteTransport{base: base, mapURL: mapURL, opts: opts}
+}
+
+type rewriteTransport struct {
+	base   http.RoundTripper
+	mapURL func(url string) string
+	opts   []Option
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || req.Method == http.MethodHead || !isRewritable(resp) {
+		return resp, err
+	}
+	if err := decodeBody(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = RewriteHTML(resp.Body, &buf, func(src ImageSource) ImageSource {
+		src.URL = t.mapURL(src.URL)
+		return src
+	}, t.opts...)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(&buf)
+	resp.ContentLength = int64(buf.Len())
+	resp.Header.Set("Content-Length", strconv.Itoa(buf.Len()))
+	return resp, nil
+}
+
+// isRewritable reports whether the body of resp is HTML that can be
+// rewritten as it is, or after decodeBody.
+func isRewritable(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/html" {
+		return false
+	}
+	enc := resp.Header.Get("Content-Encoding")
+	return enc == "" || enc == "identity" || strings.EqualFold(enc, "gzip")
+}
+
+// decodeBody replaces a gzip-compressed body of resp with its decompressed
+// content, as the rewritten body is sent without a Content-Encoding.
+func decodeBody(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body = gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody decompresses a response body, and closes it when it is closed.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b gzipBody) Close() error {
+	return b.body.Close()
+}
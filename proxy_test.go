@@ -0,0 +1,193 @@
+package srcset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func Test_RewriteResponse(t *testing.T) {
+	const page = `<!DOCTYPE html>
+<html><head><link rel=preload as=image imagesrcset="/a.png 1x, /b.png 2x"></head>
+<body>
+<IMG Src="/a.png" srcset="/a.png 1x,
+  /b.png 2x" alt='A &amp; B'>
+<img srcset="/a.png 1x 2x,/b.png  2x">
+<img srcset="">
+<script>var s = '<img srcset="/c.png">';</script>
+<p title="/a.png 1x">/a.png 1x</p>
+<A HREF="/p?a=1&amp;b=2" Title='&lt;x&gt;'>A &amp; B</A>
+</body></html>`
+	const want = `<!DOCTYPE html>
+<html><head><link rel="preload" as="image" imagesrcset="https://cdn.example.com/a.png 1x, https://cdn.example.com/b.png 2x"></head>
+<body>
+<img src="/a.png" srcset="https://cdn.example.com/a.png 1x,
+  https://cdn.example.com/b.png 2x" alt="A &amp; B">
+<img srcset="/a.png 1x 2x,https://cdn.example.com/b.png  2x">
+<img srcset="">
+<script>var s = '<img srcset="/c.png">';</script>
+<p title="/a.png 1x">/a.png 1x</p>
+<A HREF="/p?a=1&amp;b=2" Title='&lt;x&gt;'>A &amp; B</A>
+</body></html>`
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, page)
+		case "/gzip":
+			w.Header().Set("Content-Type", "text/html")
+			if r.Header.Get("Accept-Encoding") != "gzip" {
+				io.WriteString(w, page)
+				return
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped(t, page))
+		case "/br":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, "not really br")
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, page)
+		}
+	}))
+	defer backend.Close()
+
+	target, _ := url.Parse(backend.URL)
+	rp := httputil.NewSingleHostReverseProxy(target)
+	rp.ModifyResponse = RewriteResponse(func(u string) string { return "https://cdn.example.com" + u })
+	front := httptest.NewServer(rp)
+	defer front.Close()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		want           string
+	}{
+		{name: "HTML", path: "/page", want: want},
+		{name: "Not HTML", path: "/text", want: page},
+		{name: "Gzip", path: "/gzip", acceptEncoding: "gzip", want: want},
+		{name: "Other encoding", path: "/br", want: "not really br"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, front.URL+tt.path, nil)
+			req.Header.Set("Accept-Encoding", "identity")
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			resp, err := front.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if enc := resp.Header.Get("Content-Encoding"); tt.path == "/gzip" && enc != "" {
+				t.Errorf("%q. Content-Encoding = %s, want none", tt.name, enc)
+			}
+
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("%q. body = %s, want %s", tt.name, got, tt.want)
+			}
+			if resp.ContentLength >= 0 && resp.ContentLength != int64(len(got)) {
+				t.Errorf("%q. Content-Length = %d, want %d", tt.name, resp.ContentLength, len(got))
+			}
+		})
+	}
+}
+
+func Test_RewriteTransport(t *testing.T) {
+	const page = `<P CLASS="Intro"><a HREF="/p?a=1&amp;b=2">A &amp; B</a></P>
+<img src="/a.png" srcset="/a.png 1x, /b.png 2x">`
+	const want = `<P CLASS="Intro"><a HREF="/p?a=1&amp;b=2">A &amp; B</a></P>
+<img src="/a.png" srcset="https://cdn.example.com/a.png 1x, https://cdn.example.com/b.png 2x">`
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := []byte(page)
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		case "/gzip":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "gzip")
+			body = gzipped(t, page)
+		case "/br":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "br")
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method != http.MethodHead {
+			w.Write(body)
+		}
+	}))
+	defer backend.Close()
+
+	client := &http.Client{
+		Transport: RewriteTransport(backend.Client().Transport, func(u string) string { return "https://cdn.example.com" + u }),
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{name: "HTML", method: http.MethodGet, path: "/page", want: want},
+		{name: "HEAD", method: http.MethodHead, path: "/page", want: ""},
+		{name: "Not HTML", method: http.MethodGet, path: "/text", want: page},
+		{name: "Gzip", method: http.MethodGet, path: "/gzip", want: want},
+		{name: "Other encoding", method: http.MethodGet, path: "/br", want: page},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, backend.URL+tt.path, nil)
+			req.Header.Set("Accept-Encoding", "identity")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("%q. body = %s, want %s", tt.name, got, tt.want)
+			}
+			wantLength := strconv.Itoa(len(tt.want))
+			if tt.method == http.MethodHead {
+				wantLength = strconv.Itoa(len(page))
+			}
+			if got := resp.Header.Get("Content-Length"); got != wantLength {
+				t.Errorf("%q. Content-Length = %s, want %s", tt.name, got, wantLength)
+			}
+		})
+	}
+}
+
+// gzipped returns s compressed with gzip.
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	io.WriteString(zw, s)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
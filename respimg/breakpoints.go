@@ -0,0 +1,119 @@
+package respimg
+
+import (
+	"errors"
+	"math"
+
+	"github.com/lukasbob/srcset"
+)
+
+const (
+	// DefaultMinWidth is the smallest suggested width when none is set.
+	DefaultMinWidth = 320
+	// DefaultBytesPerPixel estimates the size of a compressed image of
+	// typical quality, when no estimate is set.
+	DefaultBytesPerPixel = 0.25
+
+	// maxSuggestions bounds the number of widths suggested for a byte step.
+	maxSuggestions = 100
+)
+
+// SuggestOptions controls the widths suggested by SuggestWidths. Either Count
+// or ByteStep must be set.
+type SuggestOptions struct {
+	// MinWidth is the smallest width. It defaults to DefaultMinWidth, and is
+	// capped at the intrinsic width.
+	MinWidth int64
+
+	// Count is the number of widths to suggest. A count of 1 suggests only
+	// the intrinsic width.
+	Count int
+
+	// ByteStep is the estimated difference in file size between consecutive
+	// widths.
+	ByteStep int64
+
+	// BytesPerPixel estimates the file size of the resized images. It
+	// defaults to DefaultBytesPerPixel.
+	BytesPerPixel float64
+
+	// URL returns the URL of the candidate of the given width, as in Config.
+	URL func(img Image, width int64) string
+}
+
+// SuggestWidths suggests candidate widths for img, from the minimum width up
+// to the intrinsic width. File size grows with the area of an image, so the
+// widths are spaced such that the estimated file sizes are evenly spaced:
+// either Count widths, or widths ByteStep bytes apart.
+func SuggestWidths(img Image, opts SuggestOptions) ([]int64, error) {
+	if img.Width <= 0 || img.Height <= 0 {
+		return nil, errors.New("respimg: invalid intrinsic size")
+	}
+	if (opts.Count > 0) == (opts.ByteStep > 0) {
+		return nil, errors.New("respimg: either count or byte step must be set")
+	}
+	if opts.Count == 1 {
+		return []int64{img.Width}, nil
+	}
+
+	minWidth := opts.MinWidth
+	if minWidth <= 0 {
+		minWidth = DefaultMinWidth
+	}
+	if minWidth > img.Width {
+		minWidth = img.Width
+	}
+	bpp := opts.BytesPerPixel
+	if bpp <= 0 {
+		bpp = DefaultBytesPerPixel
+	}
+
+	var (
+		lo     = float64(minWidth) * float64(minWidth)
+		hi     = float64(img.Width) * float64(img.Width)
+		widths = []int64{minWidth}
+	)
+	add := func(sq float64) {
+		w := int64(math.Round(math.Sqrt(sq)))
+		if w > widths[len(widths)-1] && w <= img.Width {
+			widths = append(widths, w)
+		}
+	}
+
+	if opts.Count > 0 {
+		for k := 1; k < opts.Count; k++ {
+			add(lo + float64(k)*(hi-lo)/float64(opts.Count-1))
+		}
+	} else {
+		// The size of an image of width w is bpp * w * w * height / width,
+		// so a byte step is a fixed step of the squared width.
+		step := float64(opts.ByteStep) / (bpp * float64(img.Height) / float64(img.Width))
+		for sq := lo + step; sq < hi && len(widths) < maxSuggestions-1; sq += step {
+			add(sq)
+		}
+		add(hi)
+	}
+
+	return widths, nil
+}
+
+// Suggest is like SuggestWidths, but returns a set with a candidate for each
+// suggested width.
+func Suggest(img Image, opts SuggestOptions) (srcset.SourceSet, error) {
+	widths, err := SuggestWidths(img, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	urlFor := opts.URL
+	if urlFor == nil {
+		urlFor = defaultURL
+	}
+
+	set := make(srcset.SourceSet, len(widths))
+	for i, w := range widths {
+		width := w
+		set[i] = srcset.ImageSource{URL: urlFor(img, w), Width: &width}
+	}
+	return set, nil
+}
@@ -0,0 +1,81 @@
+package respimg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SuggestWidths(t *testing.T) {
+	img := Image{URL: "a.jpg", Width: 2000, Height: 1000}
+
+	tests := []struct {
+		name    string
+		img     Image
+		opts    SuggestOptions
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "Count",
+			img:  img,
+			opts: SuggestOptions{Count: 4, MinWidth: 200},
+			want: []int64{200, 1166, 1637, 2000},
+		},
+		{
+			name: "Count of one",
+			img:  img,
+			opts: SuggestOptions{Count: 1},
+			want: []int64{2000},
+		},
+		{
+			name: "Byte step",
+			img:  img,
+			opts: SuggestOptions{ByteStep: 100000, MinWidth: 400, BytesPerPixel: 0.5},
+			want: []int64{400, 748, 980, 1166, 1327, 1470, 1600, 1720, 1833, 1939, 2000},
+		},
+		{
+			name: "Small image",
+			img:  Image{URL: "a.jpg", Width: 100, Height: 100},
+			opts: SuggestOptions{Count: 3},
+			want: []int64{100},
+		},
+		{
+			name:    "Neither count nor byte step",
+			img:     img,
+			wantErr: true,
+		},
+		{
+			name:    "Both count and byte step",
+			img:     img,
+			opts:    SuggestOptions{Count: 3, ByteStep: 1000},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid intrinsic size",
+			opts:    SuggestOptions{Count: 3},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SuggestWidths(tt.img, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. SuggestWidths() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. SuggestWidths() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Suggest(t *testing.T) {
+	set, err := Suggest(Image{URL: "a.jpg", Width: 1000, Height: 500}, SuggestOptions{Count: 2, MinWidth: 500})
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+	if got, want := set.String(), "a.jpg?w=500 500w, a.jpg?w=1000 1000w"; got != want {
+		t.Errorf("Suggest() = %q, want %q", got, want)
+	}
+}
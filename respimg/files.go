@@ -0,0 +1,67 @@
+package respimg
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // register decoder for FromFiles
+	_ "image/jpeg" // register decoder for FromFiles
+	_ "image/png"  // register decoder for FromFiles
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lukasbob/srcset"
+)
+
+// FromFiles builds a set of width candidates from image files, using the
+// width decoded from each file. urlFor maps the path of a file to the URL of
+// its candidate; if it is nil, the slash-separated path is used. The
+// candidates are ordered by width.
+//
+// Decoders for GIF, JPEG and PNG are registered by this package; others,
+// such as WebP, must be registered by the program.
+func FromFiles(paths []string, urlFor func(path string) string) (srcset.SourceSet, error) {
+	if urlFor == nil {
+		urlFor = filepath.ToSlash
+	}
+
+	set := make(srcset.SourceSet, 0, len(paths))
+	for _, path := range paths {
+		cfg, err := decodeConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		width := int64(cfg.Width)
+		set = append(set, srcset.ImageSource{URL: urlFor(path), Width: &width})
+	}
+
+	sort.SliceStable(set, func(i, j int) bool { return *set[i].Width < *set[j].Width })
+	return set, nil
+}
+
+// FromGlob is like FromFiles for the files matching pattern, as understood
+// by filepath.Glob.
+func FromGlob(pattern string, urlFor func(path string) string) (srcset.SourceSet, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("respimg: no files match %q", pattern)
+	}
+	return FromFiles(paths, urlFor)
+}
+
+func decodeConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("respimg: decoding %s: %w", path, err)
+	}
+	return cfg, nil
+}
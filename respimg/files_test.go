@@ -0,0 +1,101 @@
+package respimg
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePNG(t *testing.T, path string, width int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewGray(image.Rect(0, 0, width, 1))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_FromFiles(t *testing.T) {
+	dir := t.TempDir()
+	writePNG(t, filepath.Join(dir, "large.png"), 640)
+	writePNG(t, filepath.Join(dir, "small.png"), 320)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	urlFor := func(path string) string { return "/img/" + filepath.Base(path) }
+
+	tests := []struct {
+		name    string
+		build   func() (string, error)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Files",
+			build: func() (string, error) {
+				set, err := FromFiles([]string{filepath.Join(dir, "large.png"), filepath.Join(dir, "small.png")}, urlFor)
+				return set.String(), err
+			},
+			want: "/img/small.png 320w, /img/large.png 640w",
+		},
+		{
+			name: "Glob",
+			build: func() (string, error) {
+				set, err := FromGlob(filepath.Join(dir, "*.png"), urlFor)
+				return set.String(), err
+			},
+			want: "/img/small.png 320w, /img/large.png 640w",
+		},
+		{
+			name: "Default URL",
+			build: func() (string, error) {
+				set, err := FromFiles([]string{filepath.Join(dir, "small.png")}, nil)
+				return strings.TrimPrefix(set.String(), filepath.ToSlash(dir)), err
+			},
+			want: "/small.png 320w",
+		},
+		{
+			name: "Not an image",
+			build: func() (string, error) {
+				set, err := FromFiles([]string{filepath.Join(dir, "notes.txt")}, urlFor)
+				return set.String(), err
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing file",
+			build: func() (string, error) {
+				set, err := FromFiles([]string{filepath.Join(dir, "missing.png")}, urlFor)
+				return set.String(), err
+			},
+			wantErr: true,
+		},
+		{
+			name: "No matches",
+			build: func() (string, error) {
+				set, err := FromGlob(filepath.Join(dir, "*.jpg"), urlFor)
+				return set.String(), err
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("%q. got %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package respimg generates the srcset and sizes attributes of responsive
+// images from an image's intrinsic size and a breakpoint configuration, as
+// done by static site generators.
+package respimg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lukasbob/srcset"
+)
+
+// Image is the original image that candidates are generated from.
+type Image struct {
+	URL    string
+	Width  int64 // intrinsic width in pixels
+	Height int64 // intrinsic height in pixels
+}
+
+// Breakpoint sets the size of the image slot for viewports at least MinWidth
+// CSS pixels wide.
+type Breakpoint struct {
+	MinWidth int64
+	Size     string // a CSS length such as "50vw" or "320px"
+}
+
+// Config controls how candidates are generated.
+type Config struct {
+	// Widths are the candidate widths in pixels. Widths larger than the
+	// intrinsic width are replaced by the intrinsic width, so images are
+	// never upscaled.
+	Widths []int64
+
+	// Breakpoints are used to build the sizes attribute, in any order.
+	Breakpoints []Breakpoint
+
+	// DefaultSize is the slot size when no breakpoint applies. It defaults to
+	// "100vw".
+	DefaultSize string
+
+	// URL returns the URL of the candidate of the given width. By default,
+	// the width is added to the URL of the image as the "w" query parameter.
+	URL func(img Image, width int64) string
+
+	// Resize, if set, is called for each candidate so that the resized image
+	// can be created, for example by writing it to disk.
+	Resize func(img Image, width, height int64, url string) error
+}
+
+// Result holds the generated attributes.
+type Result struct {
+	Src    string // the URL of the largest candidate, for the src attribute
+	Srcset srcset.SourceSet
+	Sizes  srcset.SizeList
+	Width  int64 // the intrinsic width, for the width attribute
+	Height int64 // the intrinsic height, for the height attribute
+}
+
+// Generate generates the candidates of img as configured by cfg.
+func Generate(img Image, cfg Config) (Result, error) {
+	if img.Width <= 0 || img.Height <= 0 {
+		return Result{}, fmt.Errorf("respimg: invalid intrinsic size %dx%d", img.Width, img.Height)
+	}
+	if len(cfg.Widths) == 0 {
+		return Result{}, errors.New("respimg: no widths")
+	}
+
+	urlFor := cfg.URL
+	if urlFor == nil {
+		urlFor = defaultURL
+	}
+
+	sizes, err := buildSizes(cfg)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Sizes: sizes, Width: img.Width, Height: img.Height}
+	for _, w := range candidateWidths(cfg.Widths, img.Width) {
+		h := int64(math.Round(float64(w) * float64(img.Height) / float64(img.Width)))
+		url := urlFor(img, w)
+		if cfg.Resize != nil {
+			if err := cfg.Resize(img, w, h, url); err != nil {
+				return Result{}, fmt.Errorf("respimg: resizing to %dw: %w", w, err)
+			}
+		}
+
+		width := w
+		res.Srcset = append(res.Srcset, srcset.ImageSource{URL: url, Width: &width})
+		res.Src = url
+	}
+
+	return res, nil
+}
+
+// candidateWidths returns the positive widths, capped at max, sorted and
+// without duplicates.
+func candidateWidths(widths []int64, max int64) []int64 {
+	var out []int64
+	seen := map[int64]bool{}
+	for _, w := range widths {
+		if w > max {
+			w = max
+		}
+		if w > 0 && !seen[w] {
+			seen[w] = true
+			out = append(out, w)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// buildSizes builds the sizes attribute from the breakpoints, widest first so
+// that the first matching entry is the one for the widest breakpoint.
+func buildSizes(cfg Config) (srcset.SizeList, error) {
+	bps := append([]Breakpoint(nil), cfg.Breakpoints...)
+	sort.Slice(bps, func(i, j int) bool { return bps[i].MinWidth > bps[j].MinWidth })
+
+	def := cfg.DefaultSize
+	if def == "" {
+		def = "100vw"
+	}
+
+	entries := make([]string, 0, len(bps)+1)
+	for _, bp := range bps {
+		entries = append(entries, fmt.Sprintf("(min-width: %dpx) %s", bp.MinWidth, bp.Size))
+	}
+	entries = append(entries, def)
+
+	var warnings []srcset.Warning
+	sizes := srcset.ParseSizes(strings.Join(entries, ", "), srcset.WithWarningHandler(func(w srcset.Warning) {
+		warnings = append(warnings, w)
+	}))
+	if len(warnings) > 0 {
+		return nil, fmt.Errorf("respimg: invalid size %q", warnings[0].Text)
+	}
+	return sizes, nil
+}
+
+func defaultURL(img Image, width int64) string {
+	sep := "?"
+	if strings.Contains(img.URL, "?") {
+		sep = "&"
+	}
+	return img.URL + sep + "w=" + strconv.FormatInt(width, 10)
+}
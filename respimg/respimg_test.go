@@ -0,0 +1,119 @@
+package respimg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_Generate(t *testing.T) {
+	tests := []struct {
+		name       string
+		img        Image
+		cfg        Config
+		wantSrc    string
+		wantSrcset string
+		wantSizes  string
+		wantErr    bool
+	}{
+		{
+			name:       "Default URLs and sizes",
+			img:        Image{URL: "/hero.jpg", Width: 1200, Height: 800},
+			cfg:        Config{Widths: []int64{640, 320}},
+			wantSrc:    "/hero.jpg?w=640",
+			wantSrcset: "/hero.jpg?w=320 320w, /hero.jpg?w=640 640w",
+			wantSizes:  "100vw",
+		},
+		{
+			name:       "Widths capped at intrinsic width",
+			img:        Image{URL: "/hero.jpg?v=1", Width: 1000, Height: 800},
+			cfg:        Config{Widths: []int64{640, 1280, 1920}},
+			wantSrc:    "/hero.jpg?v=1&w=1000",
+			wantSrcset: "/hero.jpg?v=1&w=640 640w, /hero.jpg?v=1&w=1000 1000w",
+			wantSizes:  "100vw",
+		},
+		{
+			name: "Invalid size",
+			img:  Image{URL: "/hero.jpg", Width: 1200, Height: 800},
+			cfg: Config{
+				Widths:      []int64{400},
+				Breakpoints: []Breakpoint{{MinWidth: 640, Size: "wide"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Breakpoints and URL hook",
+			img:  Image{URL: "/hero.jpg", Width: 1200, Height: 800},
+			cfg: Config{
+				Widths: []int64{400},
+				Breakpoints: []Breakpoint{
+					{MinWidth: 640, Size: "50vw"},
+					{MinWidth: 1024, Size: "400px"},
+				},
+				URL: func(img Image, width int64) string { return fmt.Sprintf("/hero-%d.jpg", width) },
+			},
+			wantSrc:    "/hero-400.jpg",
+			wantSrcset: "/hero-400.jpg 400w",
+			wantSizes:  "(min-width: 1024px) 400px, (min-width: 640px) 50vw, 100vw",
+		},
+		{
+			name:    "Invalid intrinsic size",
+			img:     Image{URL: "/hero.jpg"},
+			cfg:     Config{Widths: []int64{400}},
+			wantErr: true,
+		},
+		{
+			name:    "No widths",
+			img:     Image{URL: "/hero.jpg", Width: 1200, Height: 800},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(tt.img, tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. Generate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Src != tt.wantSrc {
+				t.Errorf("%q. Generate().Src = %q, want %q", tt.name, got.Src, tt.wantSrc)
+			}
+			if s := got.Srcset.String(); s != tt.wantSrcset {
+				t.Errorf("%q. Generate().Srcset = %q, want %q", tt.name, s, tt.wantSrcset)
+			}
+			if s := got.Sizes.String(); s != tt.wantSizes {
+				t.Errorf("%q. Generate().Sizes = %q, want %q", tt.name, s, tt.wantSizes)
+			}
+			if got.Width != tt.img.Width || got.Height != tt.img.Height {
+				t.Errorf("%q. Generate() size = %dx%d, want %dx%d", tt.name, got.Width, got.Height, tt.img.Width, tt.img.Height)
+			}
+		})
+	}
+}
+
+func Test_Generate_resize(t *testing.T) {
+	var resized []string
+	cfg := Config{
+		Widths: []int64{300, 600},
+		Resize: func(img Image, width, height int64, url string) error {
+			resized = append(resized, fmt.Sprintf("%dx%d %s", width, height, url))
+			return nil
+		},
+	}
+	if _, err := Generate(Image{URL: "a.jpg", Width: 900, Height: 600}, cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want := []string{"300x200 a.jpg?w=300", "600x400 a.jpg?w=600"}
+	if fmt.Sprint(resized) != fmt.Sprint(want) {
+		t.Errorf("Resize calls = %q, want %q", resized, want)
+	}
+
+	errResize := errors.New("disk full")
+	cfg.Resize = func(Image, int64, int64, string) error { return errResize }
+	if _, err := Generate(Image{URL: "a.jpg", Width: 900, Height: 600}, cfg); !errors.Is(err, errResize) {
+		t.Errorf("Generate() error = %v, want %v", err, errResize)
+	}
+}
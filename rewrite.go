@@ -0,0 +1,159 @@
+package srcset
+
+import (
+	"html"
+	"io"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// RewriteHTML copies the HTML document from r to w, passing every candidate
+// of srcset and imagesrcset attributes through fn. The document is tokenized
+// rather than parsed into a tree, so it is processed as a stream in constant
+// memory, and everything but the tags with rewritten attributes is copied
+// byte for byte. Within the rewritten attributes, only the URLs, and the
+// descriptors fn changes, are replaced, and invalid candidates are kept.
+// Attributes without valid candidates are left as they are.
+//
+// The options are used to parse the attribute values. The rewritten
+// attributes are configured by WithAttributeNames.
+func RewriteHTML(r io.Reader, w io.Writer, fn func(ImageSource) ImageSource, opts ...Option) error {
+	var (
+		cfg    = newConfig(opts)
+		attrs  = nameSet(cfg.attributeNames().Srcset)
+		z      = xhtml.NewTokenizer(r)
+		buf    []byte
+		rawBuf []byte
+	)
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return err
+			}
+			return nil
+		}
+
+		raw := z.Raw()
+		if tt == xhtml.StartTagToken || tt == xhtml.SelfClosingTagToken {
+			// Token lowercases and unescapes the attributes in the buffer
+			// that Raw returns, so the raw tag is copied first.
+			rawBuf = append(rawBuf[:0], raw...)
+			raw = rawBuf
+			buf = rewriteTag(buf[:0], z.Token(), tt, attrs, cfg, fn)
+			if buf != nil {
+				raw = buf
+			}
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+}
+
+// rewriteTag appends tok with its srcset attributes rewritten to dst, or
+// returns nil if it has none.
+func rewriteTag(dst []byte, tok xhtml.Token, tt xhtml.TokenType, attrs map[string]bool, cfg *config, fn func(ImageSource) ImageSource) []byte {
+	found := false
+	for _, attr := range tok.Attr {
+		if attr.Namespace == "" && attrs[attr.Key] {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	dst = append(dst, '<')
+	dst = append(dst, tok.Data...)
+	for _, attr := range tok.Attr {
+		dst = append(dst, ' ')
+		if attr.Namespace != "" {
+			dst = append(dst, attr.Namespace...)
+			dst = append(dst, ':')
+		}
+		dst = append(dst, attr.Key...)
+		dst = append(dst, `="`...)
+		if attr.Namespace == "" && attrs[attr.Key] {
+			dst = appendRewritten(dst, attr.Val, cfg, fn)
+		} else {
+			dst = append(dst, html.EscapeString(attr.Val)...)
+		}
+		dst = append(dst, '"')
+	}
+	if tt == xhtml.SelfClosingTagToken {
+		dst = append(dst, " /"...)
+	}
+	return append(dst, '>')
+}
+
+// appendRewritten appends the srcset value with its candidates passed
+// through fn, or the original value if it has no valid candidates. Only the
+// URLs, and the descriptors fn changes, are replaced, so that invalid
+// candidates, whitespace and commas are kept as they are.
+func appendRewritten(dst []byte, value string, cfg *config, fn func(ImageSource) ImageSource) []byte {
+	set := parse(value, cfg)
+	if len(set) == 0 {
+		return append(dst, html.EscapeString(value)...)
+	}
+	if !urlsInPlace(set, value) {
+		// The value was changed before it was parsed, as by
+		// WithEntityDecoding, so the candidates are serialized instead.
+		for i, src := range set {
+			set[i] = fn(src)
+		}
+		return set.AppendAttr(dst)
+	}
+
+	last := 0
+	for _, src := range set {
+		start, end := src.Offset, src.Offset+len(src.URL)
+		rewritten := fn(src.Clone())
+		dst = appendAttrEscaped(dst, value[last:start])
+		dst = appendAttrURL(dst, rewritten.URL)
+		last = end
+
+		rewritten.URL = src.URL
+		if !rewritten.Equal(src) {
+			dst = appendAttrEscaped(dst, string(appendDescriptors(nil, rewritten)))
+			last = descriptorsEnd(value, end)
+		}
+	}
+	return appendAttrEscaped(dst, value[last:])
+}
+
+// urlsInPlace reports whether the URL of every candidate of set is found in
+// value at its offset.
+func urlsInPlace(set SourceSet, value string) bool {
+	last := 0
+	for _, src := range set {
+		end := src.Offset + len(src.URL)
+		if src.Offset < last || end > len(value) || value[src.Offset:end] != src.URL {
+			return false
+		}
+		last = end
+	}
+	return true
+}
+
+// descriptorsEnd returns the end of the descriptors that follow the URL
+// ending at pos in value, excluding trailing whitespace.
+func descriptorsEnd(value string, pos int) int {
+	end, depth := pos, 0
+	for i := pos; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == comma && depth == 0:
+			return end
+		}
+		if !isSpace(rune(c)) {
+			end = i + 1
+		}
+	}
+	return end
+}
@@ -0,0 +1,148 @@
+package srcset
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_RewriteHTML(t *testing.T) {
+	double := func(src ImageSource) ImageSource {
+		if src.Density != nil {
+			d := *src.Density * 2
+			src.Density = &d
+		}
+		src.URL = strings.ToUpper(src.URL)
+		return src
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "No srcset",
+			input: `<p class=x>Text &amp; <b>more</b></p><!-- srcset="a.png" -->`,
+			want:  `<p class=x>Text &amp; <b>more</b></p><!-- srcset="a.png" -->`,
+		},
+		{
+			name:  "Srcset",
+			input: `<img src=a.png srcset="a.png, b.png 2x">`,
+			want:  `<img src="a.png" srcset="A.PNG, B.PNG 4x">`,
+		},
+		{
+			name:  "Self-closing tag",
+			input: `<source srcset='a.png 1x'/>`,
+			want:  `<source srcset="A.PNG 2x" />`,
+		},
+		{
+			name:  "Escaped attribute values",
+			input: `<img alt="&lt;&quot;&gt;" srcset="a.png?x=1&amp;y=2 1x">`,
+			want:  `<img alt="&lt;&#34;&gt;" srcset="A.PNG?X=1&amp;Y=2 2x">`,
+		},
+		{
+			name:  "Formatting and invalid candidates kept",
+			input: `<img srcset=" a.png   1x ,b.png,, c.png 1x 2x,d.png(1)  e.png 2x ">`,
+			want:  `<img srcset=" A.PNG 2x ,B.PNG,, c.png 1x 2x,d.png(1)  e.png 2x ">`,
+		},
+		{
+			name:  "URLs kept as written",
+			input: `<img srcset="a%20b.png, c.png?x=1&#38;y=2 2x">`,
+			want:  `<img srcset="A%20B.PNG, C.PNG?X=1&amp;Y=2 4x">`,
+		},
+		{
+			name:  "Untouched tags kept",
+			input: `<DIV CLASS="X"><a HREF="/p?a=1&amp;b=2" title='&lt;x&gt;'>A &amp; B</a><IMG SRC="a.png"></DIV>`,
+			want:  `<DIV CLASS="X"><a HREF="/p?a=1&amp;b=2" title='&lt;x&gt;'>A &amp; B</a><IMG SRC="a.png"></DIV>`,
+		},
+		{
+			name:  "Invalid srcset",
+			input: `<img srcset="a.png 1q">`,
+			want:  `<img srcset="a.png 1q">`,
+		},
+		{
+			name:  "Text containing tags",
+			input: `<textarea><img srcset="a.png"></textarea>`,
+			want:  `<textarea><img srcset="a.png"></textarea>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RewriteHTML(strings.NewReader(tt.input), &sb, double); err != nil {
+				t.Fatalf("%q. RewriteHTML() error = %v", tt.name, err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("%q. RewriteHTML() = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func Test_RewriteHTML_writeError(t *testing.T) {
+	err := RewriteHTML(strings.NewReader("<p>x</p>"), failingWriter{}, func(src ImageSource) ImageSource { return src })
+	if err == nil {
+		t.Error("RewriteHTML() error = nil, want an error")
+	}
+}
+
+func BenchmarkRewriteHTML(b *testing.B) {
+	doc := strings.Repeat(`<div><p>Lorem ipsum dolor sit amet.</p><img src="a.png" srcset="a.png 1x, b.png 2x"></div>`, 1000)
+	identity := func(src ImageSource) ImageSource { return src }
+	b.SetBytes(int64(len(doc)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := RewriteHTML(strings.NewReader(doc), io.Discard, identity); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_RewriteHTML_attributeNames(t *testing.T) {
+	const input = `<img data-src="a.png" data-srcset="a.png 1x, b.png 2x" srcset="data:image/gif;base64,R0lGODlhAQABAAAAACw= 1x">`
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "Default attributes",
+			want: `<img data-src="a.png" data-srcset="a.png 1x, b.png 2x" srcset="DATA:IMAGE/GIF;BASE64,R0LGODLHAQABAAAAACW= 1x">`,
+		},
+		{
+			name: "Lazy attributes",
+			opts: []Option{WithAttributeNames(LazyAttributeNames)},
+			want: `<img data-src="a.png" data-srcset="A.PNG 1x, B.PNG 2x" srcset="DATA:IMAGE/GIF;BASE64,R0LGODLHAQABAAAAACW= 1x">`,
+		},
+		{
+			name: "Custom attributes",
+			opts: []Option{WithAttributeNames(AttributeNames{Srcset: []string{"Data-Srcset"}})},
+			want: `<img data-src="a.png" data-srcset="A.PNG 1x, B.PNG 2x" srcset="data:image/gif;base64,R0lGODlhAQABAAAAACw= 1x">`,
+		},
+	}
+
+	upper := func(src ImageSource) ImageSource {
+		src.URL = strings.ToUpper(src.URL)
+		return src
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RewriteHTML(strings.NewReader(input), &sb, upper, tt.opts...); err != nil {
+				t.Fatalf("%q. RewriteHTML() error = %v", tt.name, err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("%q. RewriteHTML() = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
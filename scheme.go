@@ -0,0 +1,88 @@
+package srcset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSchemes are the URL schemes allowed by CheckSchemes when no
+// schemes are given.
+var DefaultSchemes = []string{"http", "https"}
+
+// CheckSchemes returns a violation for every candidate whose URL has a
+// scheme other than the allowed schemes, compared case-insensitively.
+// Relative URLs are always allowed. When "data" is allowed, only data URLs
+// with an image media type are accepted. If no schemes are given,
+// DefaultSchemes are allowed.
+func (s SourceSet) CheckSchemes(allowed ...string) []Violation {
+	if len(allowed) == 0 {
+		allowed = DefaultSchemes
+	}
+
+	var violations []Violation
+	for _, src := range s {
+		if msg, ok := checkScheme(src.URL, allowed); !ok {
+			violations = append(violations, Violation{
+				Offset:  src.Offset,
+				URL:     src.URL,
+				Message: msg,
+				Err:     ErrDisallowedScheme,
+			})
+		}
+	}
+	return violations
+}
+
+// WithAllowedSchemes drops candidates whose URL has a scheme other than the
+// allowed schemes, as described for CheckSchemes, and reports them as
+// dropped candidates.
+func WithAllowedSchemes(allowed ...string) Option {
+	if len(allowed) == 0 {
+		allowed = DefaultSchemes
+	}
+	return func(c *config) {
+		c.allowedSchemes = allowed
+	}
+}
+
+// checkScheme reports whether url is allowed, and a message if it is not.
+func checkScheme(url string, allowed []string) (string, bool) {
+	scheme, rest := urlScheme(url)
+	if scheme == "" {
+		return "", true
+	}
+
+	for _, a := range allowed {
+		if !strings.EqualFold(a, scheme) {
+			continue
+		}
+		if scheme == "data" && !strings.HasPrefix(strings.ToLower(strings.TrimLeft(rest, " ")), "image/") {
+			return "data URL without image media type", false
+		}
+		return "", true
+	}
+
+	return fmt.Sprintf("disallowed scheme %q", scheme), false
+}
+
+// urlScheme returns the lowercased scheme of url and the part after the
+// colon, or an empty scheme for relative URLs. Like browsers, it ignores
+// leading control characters and spaces, and tabs and newlines anywhere.
+func urlScheme(url string) (string, string) {
+	url = strings.TrimLeft(url, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1a\x1b\x1c\x1d\x1e\x1f ")
+	url = strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(url)
+
+	for i := 0; i < len(url); i++ {
+		c := url[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		case i > 0 && c == ':':
+			return strings.ToLower(url[:i]), url[i+1:]
+		default:
+			return "", url
+		}
+	}
+
+	return "", url
+}
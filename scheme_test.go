@@ -0,0 +1,63 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_CheckSchemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		allowed []string
+		want    []Violation
+	}{
+		{
+			name:  "Relative and allowed URLs",
+			input: "a.png 1x, /b.png 2x, https://example.com/c.png 3x, HTTP://example.com/d.png 4x",
+		},
+		{
+			name:  "JavaScript",
+			input: "javascript:alert(1) 1x, a.png 2x",
+			want:  []Violation{{Offset: 0, URL: "javascript:alert(1)", Message: `disallowed scheme "javascript"`, Err: ErrDisallowedScheme}},
+		},
+		{
+			name:  "Obfuscated scheme",
+			input: "\x01JavaScript:alert(1)",
+			want:  []Violation{{Offset: 0, URL: "\x01JavaScript:alert(1)", Message: `disallowed scheme "javascript"`, Err: ErrDisallowedScheme}},
+		},
+		{
+			name:    "Data URL not allowed",
+			input:   "data:image/png;base64,AAAA",
+			allowed: []string{"https"},
+			want:    []Violation{{Offset: 0, URL: "data:image/png;base64,AAAA", Message: `disallowed scheme "data"`, Err: ErrDisallowedScheme}},
+		},
+		{
+			name:    "Data URL allowed",
+			input:   "data:image/png;base64,AAAA 1x, data:text/html,<b> 2x",
+			allowed: []string{"https", "data"},
+			want:    []Violation{{Offset: 31, URL: "data:text/html,<b>", Message: "data URL without image media type", Err: ErrDisallowedScheme}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).CheckSchemes(tt.allowed...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. CheckSchemes() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithAllowedSchemes(t *testing.T) {
+	var warnings []Warning
+	got := Parse("javascript:alert(1) 1x, java\nscript:alert(1) 2x, a.png 3x",
+		WithAllowedSchemes(), WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+
+	if got.String() != "a.png 3x" {
+		t.Errorf("Parse() = %q, want %q", got, "a.png 3x")
+	}
+	if len(warnings) != 2 || warnings[0].Kind != DroppedCandidate {
+		t.Errorf("Parse() warnings = %v, want 2 dropped candidates", warnings)
+	}
+}
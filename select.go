@@ -0,0 +1,130 @@
+package srcset
+
+// Largest returns the candidate with the largest width. When no candidate
+// has a width descriptor, the candidate with the largest density is returned
+// instead. Candidates without descriptors count as 1x.
+func (s SourceSet) Largest() (ImageSource, bool) {
+	return s.extreme(func(a, b float64) bool { return a > b })
+}
+
+// Smallest returns the candidate with the smallest width. When no candidate
+// has a width descriptor, the candidate with the smallest density is returned
+// instead. Candidates without descriptors count as 1x.
+func (s SourceSet) Smallest() (ImageSource, bool) {
+	return s.extreme(func(a, b float64) bool { return a < b })
+}
+
+func (s SourceSet) extreme(better func(a, b float64) bool) (ImageSource, bool) {
+	var (
+		best    ImageSource
+		bestVal float64
+		found   bool
+		byWidth = len(s.Widths()) > 0
+	)
+
+	for _, src := range s {
+		var (
+			c   = src.Candidate()
+			val float64
+		)
+		switch {
+		case byWidth && !c.HasWidth:
+			continue
+		case byWidth:
+			val = float64(c.Width)
+		default:
+			val = c.density()
+		}
+
+		if !found || better(val, bestVal) {
+			best, bestVal, found = src, val, true
+		}
+	}
+
+	return best, found
+}
+
+// BestForDPR returns the candidate with the smallest density that is at
+// least dpr, falling back to the candidate with the largest density. Only
+// density candidates are considered; candidates without descriptors count as
+// 1x.
+func (s SourceSet) BestForDPR(dpr float64) (ImageSource, bool) {
+	return s.pick(dpr, func(c Candidate) (float64, bool) {
+		return c.density(), !c.HasWidth
+	})
+}
+
+// BestForViewport returns the candidate a browser would pick for a viewport
+// of the given width in CSS pixels and device pixel ratio. The source size is
+// evaluated from sizes, and width candidates are normalized to densities
+// relative to it before picking the best candidate as BestForDPR does.
+func (s SourceSet) BestForViewport(viewportWidth, dpr float64, sizes SizeList) (ImageSource, bool) {
+	return s.BestForContext(Viewport(viewportWidth), dpr, sizes)
+}
+
+// BestForContext is like BestForViewport, but evaluates sizes using ctx.
+func (s SourceSet) BestForContext(ctx EvalContext, dpr float64, sizes SizeList) (ImageSource, bool) {
+	slot := sizes.EvaluateContext(ctx)
+	return s.pick(dpr, func(c Candidate) (float64, bool) {
+		return c.effectiveDensity(slot)
+	})
+}
+
+// BestForLayout is like BestForViewport, but uses the layout width of the
+// image in CSS pixels as the source size, as browsers do for lazy-loaded
+// images with sizes="auto".
+func (s SourceSet) BestForLayout(layoutWidth, dpr float64) (ImageSource, bool) {
+	return s.pick(dpr, func(c Candidate) (float64, bool) {
+		return c.effectiveDensity(layoutWidth)
+	})
+}
+
+// effectiveDensity returns the density of c when displayed in a slot of the
+// given width in CSS pixels.
+func (c Candidate) effectiveDensity(slot float64) (float64, bool) {
+	if !c.HasWidth {
+		return c.density(), true
+	}
+	if slot <= 0 {
+		return 0, false
+	}
+	return float64(c.Width) / slot, true
+}
+
+// pick returns the candidate with the smallest density that is at least dpr,
+// falling back to the candidate with the largest density. Candidates for
+// which density reports false are skipped.
+func (s SourceSet) pick(dpr float64, density func(Candidate) (float64, bool)) (ImageSource, bool) {
+	i, _ := s.pickIndex(dpr, density)
+	if i < 0 {
+		return ImageSource{}, false
+	}
+	return s[i], true
+}
+
+// pickIndex is like pick, but returns the index of the candidate, or -1 if
+// there is none, and whether it is the fallback.
+func (s SourceSet) pickIndex(dpr float64, density func(Candidate) (float64, bool)) (int, bool) {
+	var (
+		best, largest   = -1, -1
+		bestD, largestD float64
+	)
+
+	for i, src := range s {
+		d, ok := density(src.Candidate())
+		if !ok {
+			continue
+		}
+		if largest < 0 || d > largestD {
+			largest, largestD = i, d
+		}
+		if d >= dpr && (best < 0 || d < bestD) {
+			best, bestD = i, d
+		}
+	}
+
+	if best >= 0 {
+		return best, false
+	}
+	return largest, largest >= 0
+}
@@ -0,0 +1,110 @@
+package srcset
+
+import "testing"
+
+func Test_extremes(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantLargest  string
+		wantSmallest string
+	}{
+		{
+			name:  "Empty",
+			input: "",
+		},
+		{
+			name:         "Widths",
+			input:        "b.png 640w, a.png 320w, c.png 1280w",
+			wantLargest:  "c.png",
+			wantSmallest: "a.png",
+		},
+		{
+			name:         "Densities",
+			input:        "b.png 2x, a.png, c.png 3x",
+			wantLargest:  "c.png",
+			wantSmallest: "a.png",
+		},
+		{
+			name:         "Widths take precedence",
+			input:        "a.png 4x, b.png 320w, c.png 640w",
+			wantLargest:  "c.png",
+			wantSmallest: "b.png",
+		},
+		{
+			name:         "Ties keep the first candidate",
+			input:        "a.png 1x, b.png",
+			wantLargest:  "a.png",
+			wantSmallest: "a.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := Parse(tt.input)
+			if got, ok := set.Largest(); got.URL != tt.wantLargest || ok != (tt.wantLargest != "") {
+				t.Errorf("%q. Largest() = %v, %v, want %q", tt.name, got, ok, tt.wantLargest)
+			}
+			if got, ok := set.Smallest(); got.URL != tt.wantSmallest || ok != (tt.wantSmallest != "") {
+				t.Errorf("%q. Smallest() = %v, %v, want %q", tt.name, got, ok, tt.wantSmallest)
+			}
+		})
+	}
+}
+
+func Test_BestForDPR(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		dpr   float64
+		want  string
+	}{
+		{name: "Empty", input: "", dpr: 1, want: ""},
+		{name: "Exact match", input: "a.png 1x, b.png 2x, c.png 3x", dpr: 2, want: "b.png"},
+		{name: "Round up", input: "a.png 1x, b.png 2x, c.png 3x", dpr: 1.5, want: "b.png"},
+		{name: "Unordered", input: "c.png 3x, b.png 2x, a.png", dpr: 1.1, want: "b.png"},
+		{name: "Fall back to largest", input: "a.png 1x, b.png 2x", dpr: 3, want: "b.png"},
+		{name: "Default density", input: "a.png, b.png 2x", dpr: 1, want: "a.png"},
+		{name: "Width candidates are ignored", input: "a.png 1000w", dpr: 1, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.input).BestForDPR(tt.dpr)
+			if got.URL != tt.want || ok != (tt.want != "") {
+				t.Errorf("%q. BestForDPR() = %v, %v, want %q", tt.name, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_BestForViewport(t *testing.T) {
+	const widths = "s.jpg 320w, m.jpg 640w, l.jpg 1280w, xl.jpg 2560w"
+
+	tests := []struct {
+		name     string
+		input    string
+		viewport float64
+		dpr      float64
+		sizes    string
+		want     string
+	}{
+		{name: "Empty", input: "", viewport: 400, dpr: 1, want: ""},
+		{name: "Full width", input: widths, viewport: 400, dpr: 1, want: "m.jpg"},
+		{name: "Full width, high DPR", input: widths, viewport: 400, dpr: 3, want: "l.jpg"},
+		{name: "Fixed size", input: widths, viewport: 1920, dpr: 1, sizes: "300px", want: "s.jpg"},
+		{name: "Media condition matches", input: widths, viewport: 1200, dpr: 2, sizes: "(min-width: 1000px) 50vw, 100vw", want: "l.jpg"},
+		{name: "Media condition does not match", input: widths, viewport: 800, dpr: 2, sizes: "(min-width: 1000px) 50vw, 100vw", want: "xl.jpg"},
+		{name: "Larger than all candidates", input: widths, viewport: 3000, dpr: 2, want: "xl.jpg"},
+		{name: "Densities", input: "a.png, b.png 2x", viewport: 400, dpr: 2, want: "b.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.input).BestForViewport(tt.viewport, tt.dpr, ParseSizes(tt.sizes))
+			if got.URL != tt.want || ok != (tt.want != "") {
+				t.Errorf("%q. BestForViewport() = %v, %v, want %q", tt.name, got, ok, tt.want)
+			}
+		})
+	}
+}
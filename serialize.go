@@ -0,0 +1,122 @@
+package srcset
+
+import (
+	"sort"
+	"strconv"
+)
+
+// String serializes the SourceSet into the value of a srcset attribute.
+func (s SourceSet) String() string {
+	return string(s.AppendTo(make([]byte, 0, len(s)*32)))
+}
+
+// AppendTo appends the serialized SourceSet to dst and returns the extended
+// buffer. It does not allocate when dst has enough capacity.
+func (s SourceSet) AppendTo(dst []byte) []byte {
+	for i, src := range s {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = src.AppendTo(dst)
+	}
+	return dst
+}
+
+// String serializes the candidate, such as "a.png 480w" or "b.png 2x".
+// Densities are formatted without exponent and without trailing zeros.
+func (src ImageSource) String() string {
+	return string(src.AppendTo(make([]byte, 0, len(src.URL)+16)))
+}
+
+// AppendTo appends the serialized candidate to dst and returns the extended
+// buffer. It does not allocate when dst has enough capacity.
+func (src ImageSource) AppendTo(dst []byte) []byte {
+	dst = append(dst, src.URL...)
+	return appendDescriptors(dst, src)
+}
+
+// EncodedString serializes the SourceSet like String, but percent-encodes
+// the characters of URLs that the parser would not take as part of them, so
+// that the result parses back into the same candidates. See AppendEncoded.
+func (s SourceSet) EncodedString() string {
+	return string(s.AppendEncoded(make([]byte, 0, len(s)*32)))
+}
+
+// AppendEncoded appends the serialized SourceSet to dst like AppendTo, but
+// percent-encodes whitespace and control characters in URLs as well as
+// leading and trailing commas, which would otherwise be taken as separators.
+// Commas inside URLs are kept as they are, since they are significant in
+// data URLs.
+func (s SourceSet) AppendEncoded(dst []byte) []byte {
+	for i, src := range s {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = appendEncodedURL(dst, src.URL)
+		dst = appendDescriptors(dst, src)
+	}
+	return dst
+}
+
+func appendEncodedURL(dst []byte, url string) []byte {
+	for i := 0; i < len(url); i++ {
+		if unsafeURLByte(url, i) {
+			dst = appendPercentEncoded(dst, url[i])
+		} else {
+			dst = append(dst, url[i])
+		}
+	}
+	return dst
+}
+
+// unsafeURLByte reports whether the byte at i of url must be percent-encoded
+// in a srcset attribute.
+func unsafeURLByte(url string, i int) bool {
+	c := url[i]
+	return isSpace(rune(c)) || c < 0x20 || c == 0x7f ||
+		c == comma && (i == 0 || i == len(url)-1)
+}
+
+func appendDescriptors(dst []byte, src ImageSource) []byte {
+	if src.Width != nil {
+		dst = append(dst, ' ')
+		dst = strconv.AppendInt(dst, *src.Width, 10)
+		dst = append(dst, 'w')
+	}
+	if src.Height != nil {
+		dst = append(dst, ' ')
+		dst = strconv.AppendInt(dst, *src.Height, 10)
+		dst = append(dst, 'h')
+	}
+	if src.Density != nil {
+		dst = append(dst, ' ')
+		dst = strconv.AppendFloat(dst, *src.Density, 'f', -1, 64)
+		dst = append(dst, 'x')
+	}
+	if len(src.Extensions) > 0 {
+		for _, key := range sortedKeys(src.Extensions) {
+			dst = append(dst, ' ')
+			dst = append(dst, src.Extensions[key]...)
+			dst = append(dst, key...)
+		}
+	}
+	for _, desc := range src.RawDescriptors {
+		dst = append(dst, ' ')
+		dst = append(dst, desc...)
+	}
+	return dst
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatFloat formats f without exponent and without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,135 @@
+package srcset
+
+import "testing"
+
+func Test_String(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+		want string
+	}{
+		{
+			name: "Empty",
+			set:  SourceSet{},
+			want: "",
+		},
+		{
+			name: "URL only",
+			set:  SourceSet{ImageSource{URL: "a.png"}},
+			want: "a.png",
+		},
+		{
+			name: "Densities",
+			set: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1)},
+				ImageSource{URL: "b.png", Density: fl(1.5)},
+			},
+			want: "a.png 1x, b.png 1.5x",
+		},
+		{
+			name: "Extensions",
+			set: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(2), Extensions: map[string]string{"q": "80", "f": "1"}},
+			},
+			want: "a.png 2x 1f 80q",
+		},
+		{
+			name: "Width and height",
+			set: SourceSet{
+				ImageSource{URL: "a.png", Width: i(320), Height: i(200)},
+			},
+			want: "a.png 320w 200h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.String(); got != tt.want {
+				t.Errorf("%q. String() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ImageSource_String(t *testing.T) {
+	tests := []struct {
+		name string
+		src  ImageSource
+		want string
+	}{
+		{name: "URL only", src: ImageSource{URL: "a.png"}, want: "a.png"},
+		{name: "Width", src: ImageSource{URL: "a.png", Width: i(480)}, want: "a.png 480w"},
+		{name: "Density", src: ImageSource{URL: "a.png", Density: fl(2)}, want: "a.png 2x"},
+		{name: "Fractional density", src: ImageSource{URL: "a.png", Density: fl(1.50)}, want: "a.png 1.5x"},
+		{name: "Small density", src: ImageSource{URL: "a.png", Density: fl(0.0000001)}, want: "a.png 0.0000001x"},
+		{name: "Large density", src: ImageSource{URL: "a.png", Density: fl(1e21)}, want: "a.png 1000000000000000000000x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.src.String(); got != tt.want {
+				t.Errorf("%q. String() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AppendTo(t *testing.T) {
+	set := Parse("image-1x.png 1x, image-2x.png 2x, image-3x.png 3.5x, a.png 320w 200h")
+	buf := make([]byte, 0, 256)
+
+	got := set.AppendTo(buf[:0])
+	if string(got) != set.String() {
+		t.Errorf("AppendTo() = %q, want %q", got, set.String())
+	}
+	if allocs := testing.AllocsPerRun(100, func() { set.AppendTo(buf[:0]) }); allocs != 0 {
+		t.Errorf("AppendTo() allocates %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	set := Parse("image-1x.png 1x, image-2x.png 2x, image-3x.png 3.5x, image-4x.png 4x")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = set.String()
+	}
+}
+
+func BenchmarkAppendTo(b *testing.B) {
+	set := Parse("image-1x.png 1x, image-2x.png 2x, image-3x.png 3.5x, image-4x.png 4x")
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		buf = set.AppendTo(buf[:0])
+	}
+}
+
+func Test_EncodedString(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+		want string
+	}{
+		{name: "Plain", set: Parse("a.png 1x, b.png 2x"), want: "a.png 1x, b.png 2x"},
+		{name: "Whitespace", set: SourceSet{{URL: "my image\t.png", Width: i(100)}}, want: "my%20image%09.png 100w"},
+		{name: "Commas", set: SourceSet{{URL: ",a,b,"}, {URL: "c.png", Density: fl(2)}}, want: "%2Ca,b%2C, c.png 2x"},
+		{name: "Control characters", set: SourceSet{{URL: "a\x00\x7f.png"}}, want: "a%00%7F.png"},
+		{name: "Data URL", set: Parse("data:image/svg+xml,<svg/> 1x"), want: "data:image/svg+xml,<svg/> 1x"},
+		{name: "Markup kept", set: SourceSet{{URL: `a.png?q="&"`}}, want: `a.png?q="&"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.set.EncodedString()
+			if got != tt.want {
+				t.Errorf("%q. EncodedString() = %q, want %q", tt.name, got, tt.want)
+			}
+			if again := Parse(got).EncodedString(); again != got {
+				t.Errorf("%q. EncodedString() = %q does not round-trip: %q", tt.name, got, again)
+			}
+			if n := len(Parse(got)); n != len(tt.set) {
+				t.Errorf("%q. EncodedString() = %q parses into %d candidates, want %d", tt.name, got, n, len(tt.set))
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package srcset
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultFontSize is the initial font size in pixels, used to resolve em and
+// rem lengths.
+const defaultFontSize = 16
+
+var regexLength = regexp.MustCompile(`^([+-]?(?:[0-9]+|[0-9]*\.[0-9]+)(?:[eE][+-]?[0-9]+)?)([a-z]*)$`)
+
+// length is a CSS length such as "100vw" or "320px".
+type length struct {
+	value float64
+	unit  string
+}
+
+func parseLength(input string) (length, error) {
+	m := regexLength.FindStringSubmatch(strings.ToLower(input))
+	if m == nil {
+		return length{}, errors.New("invalid length " + strconv.Quote(input))
+	}
+
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return length{}, err
+	}
+
+	switch m[2] {
+	case "":
+		if value != 0 {
+			return length{}, errors.New("missing unit in length " + strconv.Quote(input))
+		}
+	case "px", "em", "rem", "vw", "vh", "vmin", "vmax", "cm", "mm", "q", "in", "pt", "pc":
+	default:
+		return length{}, errors.New("unsupported unit in length " + strconv.Quote(input))
+	}
+
+	return length{value: value, unit: m[2]}, nil
+}
+
+// px resolves the length to CSS pixels. Zero fields of ctx are expected to
+// have been set to their defaults.
+func (l length) px(ctx EvalContext) float64 {
+	switch l.unit {
+	case "em":
+		return l.value * ctx.FontSize
+	case "rem":
+		return l.value * ctx.RootFontSize
+	case "vw":
+		return l.value * ctx.ViewportWidth / 100
+	case "vh":
+		return l.value * ctx.ViewportHeight / 100
+	case "vmin":
+		return l.value * math.Min(ctx.ViewportWidth, ctx.ViewportHeight) / 100
+	case "vmax":
+		return l.value * math.Max(ctx.ViewportWidth, ctx.ViewportHeight) / 100
+	case "cm":
+		return l.value * 96 / 2.54
+	case "mm":
+		return l.value * 96 / 25.4
+	case "q":
+		return l.value * 96 / 101.6
+	case "in":
+		return l.value * 96
+	case "pt":
+		return l.value * 96 / 72
+	case "pc":
+		return l.value * 96 / 6
+	default:
+		return l.value
+	}
+}
+
+// Size is a single entry of a sizes attribute.
+type Size struct {
+	Condition string // the media condition, empty for the default size
+	Value     string // the source size value, such as "100vw", or "auto"
+
+	cond  mediaCondition
+	value sizeValue // nil for auto
+}
+
+// Matches reports whether the media condition of the size matches a viewport
+// of the given width in CSS pixels. A size without condition always matches,
+// except for auto, which never does.
+func (s Size) Matches(viewportWidth float64) bool {
+	return s.MatchesContext(Viewport(viewportWidth))
+}
+
+// MatchesContext is like Matches, but resolves lengths using ctx.
+func (s Size) MatchesContext(ctx EvalContext) bool {
+	if s.value == nil {
+		return false
+	}
+	return s.cond == nil || s.cond.matches(ctx.withDefaults())
+}
+
+// Pixels returns the source size value in CSS pixels for a viewport of the
+// given width, or zero for auto.
+func (s Size) Pixels(viewportWidth float64) float64 {
+	return s.PixelsContext(Viewport(viewportWidth))
+}
+
+// PixelsContext is like Pixels, but resolves lengths using ctx.
+func (s Size) PixelsContext(ctx EvalContext) float64 {
+	if s.value == nil {
+		return 0
+	}
+	return s.value.px(ctx.withDefaults())
+}
+
+func (s Size) String() string {
+	if s.Condition == "" {
+		return s.Value
+	}
+	return s.Condition + " " + s.Value
+}
+
+// SizeList is the result of parsing the value of a sizes attribute.
+type SizeList []Size
+
+// ParseSizes takes the value of a sizes attribute and parses it. Entries
+// that cannot be parsed are skipped and reported to the warning handler.
+// The first entry may be "auto", which is kept in the list; see Auto.
+func ParseSizes(input string, opts ...Option) SizeList {
+	return parseSizes(input, newConfig(opts))
+}
+
+func parseSizes(input string, cfg *config) SizeList {
+	sizes := SizeList{}
+	at := NewPositioner(input)
+
+	for i, entry := range splitComponents(input) {
+		text := strings.TrimSpace(input[entry.start:entry.end])
+		offset := entry.start + strings.Index(input[entry.start:entry.end], text)
+		if text == "" {
+			cfg.report(at, SkippedGarbage, entry.start, input[entry.start:entry.end], "empty source size", ErrSyntax)
+			continue
+		}
+
+		if i == 0 && strings.EqualFold(text, "auto") {
+			sizes = append(sizes, Size{Value: text})
+			continue
+		}
+
+		condition, value := splitLastComponent(text)
+		v, err := parseSizeValue(value)
+		if err != nil {
+			cfg.report(at, DroppedCandidate, offset, text, "invalid source size value", ErrInvalidSize)
+			continue
+		}
+
+		size := Size{Condition: condition, Value: value, value: v}
+		if condition != "" {
+			if size.cond, err = parseMediaCondition(condition); err != nil {
+				cfg.report(at, DroppedCandidate, offset, text, "invalid media condition", ErrInvalidSize)
+				continue
+			}
+		}
+
+		sizes = append(sizes, size)
+	}
+
+	return sizes
+}
+
+// parseSizeValue parses a source size value: a non-negative length, or a
+// calc() expression.
+func parseSizeValue(input string) (sizeValue, error) {
+	if isCalc(input) {
+		return parseCalc(input)
+	}
+
+	l, err := parseLength(input)
+	if err != nil {
+		return nil, err
+	}
+	if l.value < 0 {
+		return nil, errors.New("negative length " + strconv.Quote(input))
+	}
+	return l, nil
+}
+
+// Auto reports whether the list starts with "auto", which makes browsers use
+// the layout width of lazy-loaded images as the source size. The remaining
+// entries are used for other images.
+func (l SizeList) Auto() bool {
+	return len(l) > 0 && l[0].value == nil
+}
+
+// EvaluateAuto is like Evaluate, but returns layoutWidth if the list starts
+// with auto and layoutWidth is positive. Browsers only do so for lazy-loaded
+// images, once their layout width is known.
+func (l SizeList) EvaluateAuto(viewportWidth, layoutWidth float64) float64 {
+	if l.Auto() && layoutWidth > 0 {
+		return layoutWidth
+	}
+	return l.Evaluate(viewportWidth)
+}
+
+// Evaluate returns the source size in CSS pixels for a viewport of the given
+// width: the value of the first entry whose media condition matches, or
+// 100vw if none does. An auto entry is skipped.
+func (l SizeList) Evaluate(viewportWidth float64) float64 {
+	return l.EvaluateContext(Viewport(viewportWidth))
+}
+
+// EvaluateContext is like Evaluate, but resolves lengths using ctx.
+func (l SizeList) EvaluateContext(ctx EvalContext) float64 {
+	ctx = ctx.withDefaults()
+	for _, size := range l {
+		if size.MatchesContext(ctx) {
+			return size.PixelsContext(ctx)
+		}
+	}
+	return ctx.ViewportWidth
+}
+
+func (l SizeList) String() string {
+	parts := make([]string, len(l))
+	for i, size := range l {
+		parts[i] = size.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+type span struct {
+	start, end int
+}
+
+// splitComponents splits input at commas that are not nested in
+// parentheses.
+func splitComponents(input string) []span {
+	var (
+		spans []span
+		depth int
+		start int
+	)
+
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case leftParens:
+			depth++
+		case rightParens:
+			if depth > 0 {
+				depth--
+			}
+		case comma:
+			if depth == 0 {
+				spans = append(spans, span{start, i})
+				start = i + 1
+			}
+		}
+	}
+
+	if strings.TrimSpace(input[start:]) != "" || len(spans) > 0 {
+		spans = append(spans, span{start, len(input)})
+	}
+
+	return spans
+}
+
+// splitLastComponent splits text at the last whitespace that is not nested
+// in parentheses, returning the trimmed parts before and after it.
+func splitLastComponent(text string) (string, string) {
+	depth := 0
+	for i := len(text) - 1; i >= 0; i-- {
+		c := text[i]
+		switch {
+		case c == rightParens:
+			depth++
+		case c == leftParens:
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && isSpace(rune(c)):
+			return strings.TrimSpace(text[:i]), text[i+1:]
+		}
+	}
+	return "", text
+}
@@ -0,0 +1,109 @@
+package srcset
+
+import "testing"
+
+func Test_ParseSizes(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         string
+		wantWarnings int
+	}{
+		{name: "Empty", input: "", want: ""},
+		{name: "Default only", input: "100vw", want: "100vw"},
+		{name: "Media condition", input: "(max-width: 600px) 480px, 800px", want: "(max-width: 600px) 480px, 800px"},
+		{name: "Whitespace", input: " (min-width:600px)\n 50vw ,\t100vw ", want: "(min-width:600px) 50vw, 100vw"},
+		{name: "Compound condition", input: "(min-width: 600px) and (max-width: 900px) 50vw, 100vw", want: "(min-width: 600px) and (max-width: 900px) 50vw, 100vw"},
+		{name: "Invalid value", input: "(max-width: 600px) 50%, 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Negative value", input: "-10px, 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Unitless value", input: "10, 0", want: "0", wantWarnings: 1},
+		{name: "Invalid condition", input: "(max-width: 600px) or (min-width: 900px) and (width) 50vw, 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Unsupported feature", input: "(orientation: portrait) 50vw, 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Empty entry", input: "50vw,,100vw", want: "50vw, 100vw", wantWarnings: 1},
+		{name: "Calc", input: "(min-width: 800px) calc(50vw - 2rem), CALC(100vw*0.5)", want: "(min-width: 800px) calc(50vw - 2rem), CALC(100vw*0.5)"},
+		{name: "Calc without whitespace around minus", input: "calc(100vw-2rem), 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Calc adding number to length", input: "calc(100vw + 2), 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Calc multiplying lengths", input: "calc(10px * 2px), 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Calc dividing by zero", input: "calc(10px / (1 - 1)), 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Calc resulting in number", input: "calc(2 * 3), 100vw", want: "100vw", wantWarnings: 1},
+		{name: "Calc unbalanced", input: "calc((10px + 2px), 100vw", want: "", wantWarnings: 1},
+		{name: "Auto", input: "AUTO, (min-width: 800px) 50vw, 100vw", want: "AUTO, (min-width: 800px) 50vw, 100vw"},
+		{name: "Auto not first", input: "100vw, auto", want: "100vw", wantWarnings: 1},
+		{name: "Auto with condition", input: "(min-width: 800px) auto, 100vw", want: "100vw", wantWarnings: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings int
+			got := ParseSizes(tt.input, WithWarningHandler(func(Warning) { warnings++ }))
+			if got.String() != tt.want {
+				t.Errorf("%q. ParseSizes() = %q, want %q", tt.name, got, tt.want)
+			}
+			if warnings != tt.wantWarnings {
+				t.Errorf("%q. ParseSizes() warnings = %d, want %d", tt.name, warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    string
+		viewport float64
+		want     float64
+	}{
+		{name: "No sizes", sizes: "", viewport: 1000, want: 1000},
+		{name: "Viewport width", sizes: "50vw", viewport: 1000, want: 500},
+		{name: "Pixels", sizes: "320px", viewport: 1000, want: 320},
+		{name: "Ems", sizes: "20em", viewport: 1000, want: 320},
+		{name: "Inches", sizes: "2in", viewport: 1000, want: 192},
+		{name: "Min width matches", sizes: "(min-width: 800px) 400px, 100vw", viewport: 800, want: 400},
+		{name: "Min width does not match", sizes: "(min-width: 800px) 400px, 100vw", viewport: 799, want: 799},
+		{name: "Max width in ems", sizes: "(max-width: 40em) 100vw, 640px", viewport: 600, want: 600},
+		{name: "Not", sizes: "(not (min-width: 800px)) 100vw, 800px", viewport: 1000, want: 800},
+		{name: "And", sizes: "(min-width: 400px) and (max-width: 800px) 50vw, 100vw", viewport: 600, want: 300},
+		{name: "Or", sizes: "(max-width: 400px) or (min-width: 800px) 50vw, 100vw", viewport: 600, want: 600},
+		{name: "Range", sizes: "(400px <= width < 800px) 50vw, 100vw", viewport: 400, want: 200},
+		{name: "Range exclusive", sizes: "(400px <= width < 800px) 50vw, 100vw", viewport: 800, want: 800},
+		{name: "Boolean width", sizes: "(width) 10px, 100vw", viewport: 800, want: 10},
+		{name: "Calc", sizes: "calc(100vw - 2rem)", viewport: 800, want: 768},
+		{name: "Calc precedence", sizes: "calc(10px + 2 * 5px - 100px / 4 + 10px)", viewport: 800, want: 5},
+		{name: "Calc clamped", sizes: "calc(10px - 20px)", viewport: 800, want: 0},
+		{name: "Calc parentheses", sizes: "calc((100vw - 20px) / 2)", viewport: 820, want: 400},
+		{name: "Nested calc", sizes: "calc(2 * calc(25vw + 1em))", viewport: 800, want: 432},
+		{name: "Calc exponent", sizes: "calc(1e2px + 1e-1px*10)", viewport: 800, want: 101},
+		{name: "Calc signed operand", sizes: "calc(-10px + 100vw)", viewport: 800, want: 790},
+		{name: "Auto only", sizes: "auto", viewport: 800, want: 800},
+		{name: "Auto with fallback", sizes: "auto, (min-width: 600px) 50vw, 100vw", viewport: 800, want: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSizes(tt.sizes).Evaluate(tt.viewport); got != tt.want {
+				t.Errorf("%q. Evaluate() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_EvaluateAuto(t *testing.T) {
+	tests := []struct {
+		name   string
+		sizes  string
+		layout float64
+		want   float64
+	}{
+		{name: "Auto", sizes: "auto, 50vw", layout: 320, want: 320},
+		{name: "Auto without layout width", sizes: "auto, 50vw", layout: 0, want: 400},
+		{name: "Not auto", sizes: "50vw", layout: 320, want: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSizes(tt.sizes).EvaluateAuto(800, tt.layout); got != tt.want {
+				t.Errorf("%q. EvaluateAuto() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+//go:build go1.21
+
+package srcset
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger makes the parser log every warning to l at debug level, in
+// addition to calling the warning handler.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *config) {
+		c.log = func(w Warning) {
+			l.LogAttrs(context.Background(), slog.LevelDebug, "srcset: "+w.Message,
+				slog.String("kind", w.Kind.String()),
+				slog.Int("offset", w.Offset),
+				slog.Int("line", w.Line),
+				slog.Int("column", w.Column),
+				slog.String("text", w.Text),
+			)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+//go:build go1.21
+
+package srcset
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	var warnings int
+	Parse("a.png 1q,, b.png 2x", WithLogger(logger), WithWarningHandler(func(Warning) { warnings++ }))
+
+	want := []string{
+		`level=DEBUG msg="srcset: invalid descriptors" kind="dropped candidate" offset=0 line=1 column=1 text="a.png 1q"`,
+		`level=DEBUG msg="srcset: extraneous commas" kind="skipped garbage" offset=9 line=1 column=10 text=", "`,
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("WithLogger() logged\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if warnings != 2 {
+		t.Errorf("warning handler called %d times, want 2", warnings)
+	}
+}
+
+func Test_WithLogger_level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	Parse("a.png 1q", WithLogger(logger))
+	if buf.Len() != 0 {
+		t.Errorf("WithLogger() logged %q at the default level, want nothing", buf.String())
+	}
+}
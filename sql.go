@@ -0,0 +1,31 @@
+package srcset
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer. The SourceSet is stored as the value of a
+// srcset attribute, and a nil SourceSet as NULL, so that Scan restores it.
+func (s SourceSet) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return s.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts string and []byte values, which are
+// parsed with ParseStrict. A NULL value results in a nil SourceSet.
+func (s *SourceSet) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return s.UnmarshalText([]byte(v))
+	case []byte:
+		return s.UnmarshalText(v)
+	default:
+		return fmt.Errorf("srcset: cannot scan %T into SourceSet", src)
+	}
+}
@@ -0,0 +1,90 @@
+package srcset
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = SourceSet{}
+	_ sql.Scanner   = &SourceSet{}
+)
+
+func Test_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    SourceSet
+		wantErr bool
+	}{
+		{
+			name: "String",
+			src:  "a.png 1x, b.png 2x",
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 10},
+			},
+		},
+		{
+			name: "Bytes",
+			src:  []byte("a.png 320w"),
+			want: SourceSet{
+				ImageSource{URL: "a.png", Width: i(320), Offset: 0},
+			},
+		},
+		{
+			name: "Null",
+			src:  nil,
+			want: nil,
+		},
+		{
+			name:    "Invalid",
+			src:     "a.png 0w",
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported type",
+			src:     42,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SourceSet
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. Scan() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Scan() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Value(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+		want driver.Value
+	}{
+		{name: "Set", set: SourceSet{ImageSource{URL: "a.png", Width: i(320)}}, want: "a.png 320w"},
+		{name: "Empty", set: SourceSet{}, want: ""},
+		{name: "Nil", set: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.set.Value()
+			if err != nil {
+				t.Fatalf("%q. Value() error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%q. Value() = %#v, want %#v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,8 +5,13 @@
 package srcset
 
 import (
+	"html"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ImageSource is a structure that contains an image definition.
@@ -16,6 +21,13 @@ type ImageSource struct {
 	Height  *int64
 	Density *float64
 	Offset  int
+
+	// Extensions holds the values of custom descriptors, keyed by their
+	// suffix. See WithDescriptorHandler.
+	Extensions map[string]string
+	// RawDescriptors holds unrecognized descriptors verbatim. See
+	// WithForwardCompatibility.
+	RawDescriptors []string
 }
 
 // SourceSet is the result of parsing the value of a srcset attribute.
@@ -59,7 +71,94 @@ func isSpace(c rune) bool {
 }
 
 // Parse takes the value of a srcset attribute and parses it.
-func Parse(input string) SourceSet {
+func Parse(input string, opts ...Option) SourceSet {
+	return parse(input, newConfig(opts))
+}
+
+// ParseStrict is like Parse, but returns a *ParseError describing every
+// problem found in the input, if any, or only the first with WithFailFast.
+// Fixable warnings are not problems, as they report input accepted by an
+// option such as WithLenientDescriptorCase; they are only passed to the
+// warning handler. The returned SourceSet contains the candidates that could
+// be parsed regardless.
+func ParseStrict(input string, opts ...Option) (SourceSet, error) {
+	return parseStrict(input, newConfig(opts))
+}
+
+// Parser parses srcset attributes using a fixed set of options. Internal
+// buffers are pooled and reused across calls, so parsing large amounts of
+// attributes creates little garbage. A Parser is safe for concurrent use.
+type Parser struct {
+	cfg *config
+}
+
+// NewParser returns a Parser configured with opts.
+func NewParser(opts ...Option) *Parser {
+	return &Parser{cfg: newConfig(opts)}
+}
+
+// Parse takes the value of a srcset attribute and parses it.
+func (p *Parser) Parse(input string) SourceSet {
+	return parse(input, p.cfg)
+}
+
+// ParseStrict is like Parse, but returns a *ParseError describing every
+// problem found in the input, if any.
+func (p *Parser) ParseStrict(input string) (SourceSet, error) {
+	cfg := *p.cfg
+	return parseStrict(input, &cfg)
+}
+
+func parseStrict(input string, cfg *config) (SourceSet, error) {
+	var (
+		warnings []Warning
+		handler  = cfg.warn
+	)
+
+	cfg.warn = func(w Warning) {
+		if handler != nil {
+			handler(w)
+		}
+		// Fixable warnings report input that an option made acceptable,
+		// so they are only passed to the handler.
+		if w.Kind == Fixable {
+			return
+		}
+		warnings = append(warnings, w)
+		cfg.stopped = cfg.failFast
+	}
+
+	set := parse(input, cfg)
+	if len(warnings) > 0 {
+		return set, &ParseError{Input: input, Warnings: warnings}
+	}
+	return set, nil
+}
+
+// scratch holds buffers that are reused across calls to parse.
+type scratch struct {
+	descriptors []string
+}
+
+var scratchPool = sync.Pool{
+	New: func() interface{} { return &scratch{} },
+}
+
+func parse(input string, cfg *config) SourceSet {
+	if cfg.decodeEntities {
+		input = html.UnescapeString(input)
+	}
+	// Warnings are positioned in source, as the replacement of whitespace
+	// keeps offsets but not columns.
+	source := input
+	at := NewPositioner(source)
+	if cfg.lenientSpace {
+		input = replaceUnicodeSpaces(input, at, cfg)
+	}
+
+	sc := scratchPool.Get().(*scratch)
+	defer scratchPool.Put(sc)
+
 	var (
 		url         string
 		urlPos      = 0
@@ -67,9 +166,44 @@ func Parse(input string) SourceSet {
 		currState   = stateNone
 		end         = len(input)
 		candidates  = SourceSet{}
-		descriptors = []string{}
+		descriptors = sc.descriptors[:0]
 	)
 
+	// Descriptors are substrings of input, so they can be collected without
+	// allocating. They are cleared before the buffer is returned to the pool,
+	// so that it does not keep input alive.
+	defer func() {
+		descriptors = descriptors[:cap(descriptors)]
+		for i := range descriptors {
+			descriptors[i] = ""
+		}
+		sc.descriptors = descriptors[:0]
+	}()
+
+	// commit hands the candidates over to the arena, if there is one.
+	commit := func(s SourceSet) SourceSet {
+		if cfg.arena == nil {
+			return s
+		}
+		return cfg.arena.commit(s)
+	}
+	if cfg.arena != nil {
+		candidates = cfg.arena.sourceSet()
+	}
+
+	if cfg.stats != nil {
+		defer func() { cfg.stats.ParsedCandidates(len(candidates)) }()
+	}
+
+	// drop reports a dropped candidate. The reason is a fixed string for the
+	// StatsRecorder, while the message may hold details.
+	drop := func(offset int, text, reason, message string, err error) {
+		cfg.report(at, DroppedCandidate, offset, text, message, err)
+		if cfg.stats != nil {
+			cfg.stats.DroppedCandidate(reason)
+		}
+	}
+
 	collectChars := func(rx *regexp.Regexp) (string, int) {
 		if match := rx.FindString(input[pos:]); match != "" {
 			pos += len(match)
@@ -81,71 +215,142 @@ func Parse(input string) SourceSet {
 
 	parseDescriptors := func() {
 		var (
-			isErr = false
-			h     *int64
-			w     *int64
-			d     *float64
+			err        error
+			c          = Candidate{URL: url, Offset: urlPos}
+			extensions map[string]string
+			raw        []string
 		)
 
+		// fail records the first problem with the descriptors.
+		fail := func(e error) {
+			if err == nil {
+				err = e
+			}
+		}
+
+		// Descriptors follow each other in input, so their offsets are
+		// found by searching from the end of the previous one.
+		descPos := urlPos + len(url)
 		for _, desc := range descriptors {
 			lastIdx := len(desc) - 1
 			lastChar, numericVal := desc[lastIdx], desc[:lastIdx]
+			if cfg.lenientCase {
+				descOffset := descPos + strings.Index(input[descPos:], desc)
+				descPos = descOffset + len(desc)
+				if lastChar == 'W' || lastChar == 'X' || lastChar == 'H' {
+					lastChar += 'a' - 'A'
+					cfg.reportFix(at, descOffset, desc, numericVal+string(lastChar), "uppercase descriptor suffix", ErrInvalidDescriptor)
+				}
+			}
 			intVal, intErr := strconv.ParseInt(numericVal, 10, 64)
 			floatVal, floatErr := strconv.ParseFloat(numericVal, 64)
 
 			switch {
 			case regexNonNegativeInteger.MatchString(numericVal) && lastChar == 'w':
-				if w != nil || d != nil {
-					isErr = true
+				if c.HasWidth {
+					fail(ErrDuplicateDescriptor)
+				} else if c.HasDensity {
+					fail(ErrMixedDescriptors)
 				}
-				if intErr != nil || intVal == 0 {
-					isErr = true
+				if intErr != nil {
+					fail(ErrInvalidDescriptor)
+				} else if intVal == 0 {
+					fail(ErrZeroWidth)
 				} else {
-					w = &intVal
+					c.Width, c.HasWidth = intVal, true
 				}
 			case regexFloatingPoint.MatchString(numericVal) && lastChar == 'x':
-				if w != nil || d != nil || h != nil {
-					isErr = true
+				if c.HasDensity {
+					fail(ErrDuplicateDescriptor)
+				} else if c.HasWidth || c.HasHeight {
+					fail(ErrMixedDescriptors)
 				}
-				if floatErr != nil || floatVal < 0 {
-					isErr = true
+				if floatErr != nil {
+					fail(ErrInvalidDescriptor)
+				} else if floatVal < 0 {
+					fail(ErrNegativeDensity)
 				} else {
-					d = &floatVal
+					c.Density, c.HasDensity = floatVal, true
 				}
 			case regexNonNegativeInteger.MatchString(numericVal) && lastChar == 'h':
-				if h != nil || d != nil {
-					isErr = true
+				if c.HasHeight {
+					fail(ErrDuplicateDescriptor)
+				} else if c.HasDensity {
+					fail(ErrMixedDescriptors)
 				}
-				if intErr != nil || intVal == 0 {
-					isErr = true
+				if intErr != nil {
+					fail(ErrInvalidDescriptor)
+				} else if intVal == 0 {
+					fail(ErrInvalidHeight)
 				} else {
-					h = &intVal
+					c.Height, c.HasHeight = intVal, true
+				}
+			case cfg.descriptorHandlers[lastChar] != nil:
+				key := string(lastChar)
+				if _, ok := extensions[key]; ok {
+					fail(ErrDuplicateDescriptor)
+					break
+				}
+				value, handlerErr := cfg.descriptorHandlers[lastChar](numericVal)
+				if handlerErr != nil {
+					fail(ErrInvalidDescriptor)
+					break
+				}
+				if extensions == nil {
+					extensions = map[string]string{}
 				}
+				extensions[key] = value
+			case cfg.forwardCompat && lastChar != 'w' && lastChar != 'x' && lastChar != 'h':
+				raw = append(raw, desc)
 			default:
-				isErr = true
+				fail(ErrInvalidDescriptor)
 			}
 		}
 
-		if !isErr {
-			candidates = append(candidates, ImageSource{
-				URL:     url,
-				Offset:  urlPos,
-				Density: d,
-				Width:   w,
-				Height:  h,
-			})
+		if cfg.profile == Living && c.HasHeight && !c.HasWidth {
+			fail(ErrMixedDescriptors)
+		}
+
+		if err != nil {
+			text := strings.TrimRight(input[urlPos:pos], ", \t\n\r\u000c")
+			drop(urlPos, text, ReasonInvalidDescriptors, "invalid descriptors", err)
+			return
+		}
+
+		if cfg.allowedSchemes != nil {
+			if msg, ok := checkScheme(url, cfg.allowedSchemes); !ok {
+				text := strings.TrimRight(input[urlPos:pos], ", \t\n\r\u000c")
+				drop(urlPos, text, ReasonDisallowedScheme, msg, ErrDisallowedScheme)
+				return
+			}
 		}
+
+		var src ImageSource
+		if cfg.arena != nil {
+			src = cfg.arena.imageSource(c)
+		} else {
+			src = c.ImageSource()
+		}
+		src.Extensions = extensions
+		src.RawDescriptors = raw
+		candidates = append(candidates, src)
 	}
 
 	tokenize := func() {
 		collectChars(regexLeadingSpaces)
-		currDescriptor := ""
+		descStart := -1 // start of the current descriptor, or -1 if it is empty
 		currState = stateInDescriptor
 
 		for {
 			if pos == len(input) {
-				if currState != stateAfterDescriptor && currDescriptor != "" {
-					descriptors = append(descriptors, currDescriptor)
+				if currState == stateInParens {
+					cfg.report(at, Suspicious, urlPos, input[urlPos:], "unterminated parenthesis", ErrSyntax)
+					if cfg.stopped {
+						return
+					}
+				}
+				if currState != stateAfterDescriptor && descStart >= 0 {
+					descriptors = append(descriptors, input[descStart:pos])
 				}
 
 				parseDescriptors()
@@ -158,32 +363,32 @@ func Parse(input string) SourceSet {
 			case stateInDescriptor:
 				switch {
 				case isSpace(c):
-					if currDescriptor != "" {
-						descriptors = append(descriptors, currDescriptor)
-						currDescriptor = ""
+					if descStart >= 0 {
+						descriptors = append(descriptors, input[descStart:pos])
+						descStart = -1
 						currState = stateAfterDescriptor
 					}
 				case c == comma:
-					pos++
-					if currDescriptor != "" {
-						descriptors = append(descriptors, currDescriptor)
-						parseDescriptors()
-						return
+					if descStart >= 0 {
+						descriptors = append(descriptors, input[descStart:pos])
 					}
+					pos++
+					parseDescriptors()
+					return
 				case c == leftParens:
-					currDescriptor += string(c)
+					if descStart < 0 {
+						descStart = pos
+					}
 					currState = stateInParens
 				default:
-					currDescriptor += string(c)
+					if descStart < 0 {
+						descStart = pos
+					}
 				}
 
 			case stateInParens:
-				switch c {
-				case rightParens:
-					currDescriptor += string(c)
+				if c == rightParens {
 					currState = stateInDescriptor
-				default:
-					currDescriptor += string(c)
 				}
 
 			case stateAfterDescriptor:
@@ -200,19 +405,79 @@ func Parse(input string) SourceSet {
 	}
 
 	for {
-		collectChars(regexLeadingCommasOrSpaces)
-		if pos >= end {
-			return candidates
+		if cfg.stopped {
+			return commit(candidates)
+		}
+		if skipped, skippedPos := collectChars(regexLeadingCommasOrSpaces); strings.ContainsRune(skipped, comma) {
+			cfg.report(at, SkippedGarbage, skippedPos, skipped, "extraneous commas", ErrSyntax)
+		}
+		if pos >= end || cfg.stopped {
+			return commit(candidates)
+		}
+		if cfg.maxCandidates > 0 && len(candidates) >= cfg.maxCandidates {
+			drop(pos, input[pos:], ReasonCandidateLimit, "candidate limit exceeded", ErrCandidateLimit)
+			return commit(candidates)
 		}
 
 		url, urlPos = collectChars(regexLeadingNotSpaces)
-		descriptors = []string{}
+		descriptors = descriptors[:0]
 
 		if url[len(url)-1] == ',' {
-			url = regexTrailingCommas.ReplaceAllString(url, "")
+			trimmed := regexTrailingCommas.ReplaceAllString(url, "")
+			if len(url)-len(trimmed) > 1 {
+				cfg.report(at, Suspicious, urlPos, url, "multiple trailing commas after URL", ErrSyntax)
+				if cfg.stopped {
+					return commit(candidates)
+				}
+			}
+			url = trimmed
 			parseDescriptors()
 		} else {
 			tokenize()
 		}
 	}
 }
+
+// replaceUnicodeSpaces replaces runs of whitespace outside ASCII in input,
+// such as no-break spaces, by as many ASCII spaces as they have bytes, and
+// reports each run as a Fixable warning.
+func replaceUnicodeSpaces(input string, at *Positioner, cfg *config) string {
+	var (
+		b     []byte
+		start = -1 // start of the current run, or -1
+	)
+	flush := func(end int) {
+		if start >= 0 {
+			cfg.reportFix(at, start, input[start:end], " ", "non-ASCII whitespace", ErrSyntax)
+			start = -1
+		}
+	}
+
+	for i, r := range input {
+		if r < utf8.RuneSelf || !isUnicodeSpace(r) {
+			flush(i)
+			continue
+		}
+		if b == nil {
+			b = []byte(input)
+		}
+		if start < 0 {
+			start = i
+		}
+		for j := i; j < i+utf8.RuneLen(r); j++ {
+			b[j] = ' '
+		}
+	}
+	flush(len(input))
+
+	if b == nil {
+		return input
+	}
+	return string(b)
+}
+
+// isUnicodeSpace reports whether r is whitespace, or an invisible character
+// that word processors use like it.
+func isUnicodeSpace(r rune) bool {
+	return unicode.IsSpace(r) || r == '\u200b' || r == '\ufeff'
+}
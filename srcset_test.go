@@ -107,6 +107,14 @@ func Test_parse(t *testing.T) {
 			want: SourceSet{},
 		},
 
+		{
+			name: "Space before comma",
+			args: args{"a.png 1x , b.png 2x"},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 11},
+			},
+		},
 		{
 			name: "Super funky",
 			args: args{"data:,a ( , data:,b 1x, ), data:,c"},
@@ -124,3 +132,134 @@ func Test_parse(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    SourceSet
+		wantErr bool
+	}{
+		{
+			name:  "Valid",
+			input: "a.png 1x, b.png 2x",
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 10},
+			},
+		},
+		{
+			name:  "Invalid candidate",
+			input: "a.png 1x 2x, b.png 2x",
+			want: SourceSet{
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 13},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Extraneous commas",
+			input:   ",a.png",
+			want:    SourceSet{ImageSource{URL: "a.png", Offset: 1}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStrict(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%q. ParseStrict() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ParseStrict() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseStrict_failFast(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		opts         []Option
+		want         SourceSet
+		wantWarnings int
+	}{
+		{
+			name:  "Valid",
+			input: "a.png 1x, b.png 2x",
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 10},
+			},
+		},
+		{
+			name:  "Invalid candidates",
+			input: "a.png 1x, b.png 1x 2x, c.png 3q, d.png 4x",
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:         "Extraneous commas",
+			input:        ",a.png,, b.png",
+			want:         SourceSet{},
+			wantWarnings: 1,
+		},
+		{
+			name:         "Unterminated parenthesis",
+			input:        "a.png 1x 2x (",
+			want:         SourceSet{},
+			wantWarnings: 1,
+		},
+		{
+			name:         "Multiple trailing commas",
+			input:        "a.png,, b.png 1x 2x",
+			want:         SourceSet{},
+			wantWarnings: 1,
+		},
+		{
+			name:  "Fixable warnings",
+			input: "a.png\u00a01x, b.png\u00a02x, c.png 3q, d.png 4x",
+			opts:  []Option{WithLenientWhitespace()},
+			want: SourceSet{
+				ImageSource{URL: "a.png", Density: fl(1), Offset: 0},
+				ImageSource{URL: "b.png", Density: fl(2), Offset: 11},
+			},
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStrict(tt.input, append(tt.opts, WithFailFast())...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ParseStrict() = %v, want %v", tt.name, got, tt.want)
+			}
+			var warnings int
+			if pe, ok := err.(*ParseError); ok {
+				warnings = len(pe.Warnings)
+			}
+			if warnings != tt.wantWarnings {
+				t.Errorf("%q. ParseStrict() warnings = %d, want %d", tt.name, warnings, tt.wantWarnings)
+			}
+		})
+	}
+
+	// The option does not affect Parse, nor later calls of a Parser.
+	p := NewParser(WithFailFast())
+	p.ParseStrict("a.png 1q, b.png")
+	if got := p.Parse("a.png 1q, b.png, c.png 2x"); len(got) != 2 {
+		t.Errorf("Parse() = %v, want 2 candidates", got)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	const input = `elva-fairy-320w.jpg 320w, elva-fairy-480w.jpg 480w, elva-fairy-800w.jpg 800w 600h, data:,a ( , data:,b 1x, ), data:,c`
+	p := NewParser()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		p.Parse(input)
+	}
+}
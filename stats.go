@@ -0,0 +1,88 @@
+package srcset
+
+import "sort"
+
+// Reasons for dropped candidates passed to a StatsRecorder. They are fixed
+// strings, suitable as metric labels.
+const (
+	ReasonInvalidDescriptors = "invalid_descriptors"
+	ReasonDisallowedScheme   = "disallowed_scheme"
+	ReasonCandidateLimit     = "candidate_limit"
+)
+
+// StatsRecorder receives the outcomes of parsing srcset attributes, for
+// example to update metrics. It must be safe for concurrent use when the
+// parser is used concurrently.
+type StatsRecorder interface {
+	// ParsedCandidates is called once per parsed attribute with the number
+	// of candidates in the result.
+	ParsedCandidates(n int)
+	// DroppedCandidate is called for every dropped candidate with one of the
+	// Reason constants.
+	DroppedCandidate(reason string)
+}
+
+// WithStatsRecorder makes the parser report its outcomes to r. A Parser
+// shared between goroutines, or ParseAll, calls r concurrently, in which case
+// r must be safe for concurrent use.
+func WithStatsRecorder(r StatsRecorder) Option {
+	return func(c *config) {
+		c.stats = r
+	}
+}
+
+// Stats summarizes a SourceSet for reporting.
+type Stats struct {
+	Candidates int
+	// MinWidth, MaxWidth and MedianWidth are over the width candidates,
+	// and zero if there are none.
+	MinWidth, MaxWidth int64
+	MedianWidth        float64
+	// MinDensity and MaxDensity are over the other candidates, where
+	// candidates without descriptors count as 1x, and zero if there are
+	// none.
+	MinDensity, MaxDensity float64
+	Kind                   DescriptorKind
+	// HasDefault reports whether a candidate has no descriptors.
+	HasDefault bool
+}
+
+// Stats returns a summary of s.
+func (s SourceSet) Stats() Stats {
+	var (
+		st        = Stats{Candidates: len(s), Kind: s.Kind()}
+		widths    []int64
+		densities int
+	)
+
+	for _, src := range s {
+		c := src.Candidate()
+		if c.HasWidth {
+			widths = append(widths, c.Width)
+			continue
+		}
+		if src.Kind() == KindDefault {
+			st.HasDefault = true
+		}
+		d := c.density()
+		if densities == 0 || d < st.MinDensity {
+			st.MinDensity = d
+		}
+		if densities == 0 || d > st.MaxDensity {
+			st.MaxDensity = d
+		}
+		densities++
+	}
+
+	if n := len(widths); n > 0 {
+		sort.Slice(widths, func(i, j int) bool { return widths[i] < widths[j] })
+		st.MinWidth, st.MaxWidth = widths[0], widths[n-1]
+		if n%2 == 1 {
+			st.MedianWidth = float64(widths[n/2])
+		} else {
+			st.MedianWidth = float64(widths[n/2-1]+widths[n/2]) / 2
+		}
+	}
+
+	return st
+}
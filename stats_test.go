@@ -0,0 +1,92 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+type countingRecorder struct {
+	parsed  []int
+	dropped map[string]int
+}
+
+func (r *countingRecorder) ParsedCandidates(n int) { r.parsed = append(r.parsed, n) }
+
+func (r *countingRecorder) DroppedCandidate(reason string) {
+	if r.dropped == nil {
+		r.dropped = map[string]int{}
+	}
+	r.dropped[reason]++
+}
+
+func Test_WithStatsRecorder(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		wantParsed  []int
+		wantDropped map[string]int
+	}{
+		{
+			name:       "Valid",
+			input:      "a.png 1x, b.png 2x",
+			wantParsed: []int{2},
+		},
+		{
+			name:        "Invalid descriptors",
+			input:       "a.png 1q, b.png 2x, c.png 1x 2x",
+			wantParsed:  []int{1},
+			wantDropped: map[string]int{ReasonInvalidDescriptors: 2},
+		},
+		{
+			name:        "Disallowed scheme",
+			input:       "javascript:a 1x, b.png 2x",
+			opts:        []Option{WithAllowedSchemes()},
+			wantParsed:  []int{1},
+			wantDropped: map[string]int{ReasonDisallowedScheme: 1},
+		},
+		{
+			name:        "Candidate limit",
+			input:       "a.png 1x, b.png 2x",
+			opts:        []Option{WithMaxCandidates(1)},
+			wantParsed:  []int{1},
+			wantDropped: map[string]int{ReasonCandidateLimit: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &countingRecorder{}
+			Parse(tt.input, append(tt.opts, WithStatsRecorder(r))...)
+			if !reflect.DeepEqual(r.parsed, tt.wantParsed) {
+				t.Errorf("%q. ParsedCandidates = %v, want %v", tt.name, r.parsed, tt.wantParsed)
+			}
+			if !reflect.DeepEqual(r.dropped, tt.wantDropped) {
+				t.Errorf("%q. DroppedCandidate = %v, want %v", tt.name, r.dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func Test_SourceSet_Stats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Stats
+	}{
+		{name: "Empty", input: "", want: Stats{Kind: KindDefault}},
+		{name: "Default", input: "a.png", want: Stats{Candidates: 1, MinDensity: 1, MaxDensity: 1, Kind: KindDefault, HasDefault: true}},
+		{name: "Densities", input: "a.png 2x, b.png, c.png 1.5x", want: Stats{Candidates: 3, MinDensity: 1, MaxDensity: 2, Kind: KindDensity, HasDefault: true}},
+		{name: "Odd widths", input: "a.png 640w, b.png 320w, c.png 1280w", want: Stats{Candidates: 3, MinWidth: 320, MaxWidth: 1280, MedianWidth: 640, Kind: KindWidth}},
+		{name: "Even widths", input: "a.png 640w, b.png 320w, c.png 1280w, d.png 480w", want: Stats{Candidates: 4, MinWidth: 320, MaxWidth: 1280, MedianWidth: 560, Kind: KindWidth}},
+		{name: "Mixed", input: "a.png 640w, b.png 3x", want: Stats{Candidates: 2, MinWidth: 640, MaxWidth: 640, MedianWidth: 640, MinDensity: 3, MaxDensity: 3, Kind: KindMixed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).Stats(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Stats() = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
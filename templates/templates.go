@@ -0,0 +1,97 @@
+// Package templates provides functions for html/template that render srcset
+// and related attributes.
+//
+// The functions return complete, escaped attributes, so they are used in
+// place of an attribute rather than inside an attribute value. Like the URL
+// filtering of html/template, they return an error rather than render a
+// candidate URL with a scheme other than those in srcset.DefaultSchemes,
+// such as "javascript:".
+//
+//	tmpl := template.New("img").Funcs(templates.FuncMap())
+//	tmpl.Parse(`<img {{pickSrc .Images 800}} {{srcset .Images}}>`)
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+
+	"github.com/lukasbob/srcset"
+)
+
+// FuncMap returns the functions of this package by their template names:
+//
+//	srcset        Srcset
+//	srcsetWidths  SrcsetWidths
+//	pickSrc       PickSrc
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"srcset":       Srcset,
+		"srcsetWidths": SrcsetWidths,
+		"pickSrc":      PickSrc,
+	}
+}
+
+// Srcset renders a srcset attribute. value is either a srcset.SourceSet or a
+// string, which is parsed first.
+func Srcset(value interface{}) (template.HTMLAttr, error) {
+	set, err := sourceSet(value)
+	if err != nil {
+		return "", err
+	}
+	return template.HTMLAttr(`srcset="` + set.AttrString() + `"`), nil
+}
+
+// SrcsetWidths renders a srcset attribute with a width candidate for each of
+// widths. The URL of each candidate is pattern formatted with its width, such
+// as "/img/hero-%d.jpg".
+func SrcsetWidths(pattern string, widths ...int) (template.HTMLAttr, error) {
+	if len(widths) == 0 {
+		return "", errors.New("templates: no widths")
+	}
+
+	set := make(srcset.SourceSet, len(widths))
+	for i, w := range widths {
+		if w <= 0 {
+			return "", fmt.Errorf("templates: width %d is not greater than zero", w)
+		}
+		width := int64(w)
+		set[i] = srcset.ImageSource{URL: fmt.Sprintf(pattern, w), Width: &width}
+	}
+	return Srcset(set)
+}
+
+// PickSrc renders a src attribute with the URL of the candidate a browser
+// would pick for a viewport of the given width at a device pixel ratio of 1,
+// assuming the image fills the viewport. value is either a srcset.SourceSet
+// or a string, which is parsed first.
+func PickSrc(value interface{}, viewportWidth float64) (template.HTMLAttr, error) {
+	set, err := sourceSet(value)
+	if err != nil {
+		return "", err
+	}
+	src, ok := set.BestForViewport(viewportWidth, 1, nil)
+	if !ok {
+		return "", errors.New("templates: no candidate to pick")
+	}
+	return template.HTMLAttr(`src="` + html.EscapeString(src.URL) + `"`), nil
+}
+
+// sourceSet returns value as a source set whose URLs are safe to render.
+func sourceSet(value interface{}) (srcset.SourceSet, error) {
+	var set srcset.SourceSet
+	switch v := value.(type) {
+	case srcset.SourceSet:
+		set = v
+	case string:
+		set = srcset.Parse(v)
+	default:
+		return nil, fmt.Errorf("templates: unsupported type %T", value)
+	}
+
+	if violations := set.CheckSchemes(); len(violations) > 0 {
+		return nil, fmt.Errorf("templates: %s: %w", violations[0].URL, violations[0].Err)
+	}
+	return set, nil
+}
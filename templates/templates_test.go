@@ -0,0 +1,115 @@
+package templates
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/lukasbob/srcset"
+)
+
+func Test_FuncMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		data    interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Srcset from SourceSet",
+			tmpl: `<img {{srcset .}}>`,
+			data: srcset.Parse("a.png?w=1&h=2 1x, b.png 2x"),
+			want: `<img srcset="a.png?w=1&amp;h=2 1x, b.png 2x">`,
+		},
+		{
+			name: "Srcset from string",
+			tmpl: `<img {{srcset .}}>`,
+			data: "a.png 1x,  b.png   2x",
+			want: `<img srcset="a.png 1x, b.png 2x">`,
+		},
+		{
+			name:    "Srcset from unsupported type",
+			tmpl:    `<img {{srcset .}}>`,
+			data:    42,
+			wantErr: true,
+		},
+		{
+			name:    "Srcset with javascript URL",
+			tmpl:    `<img {{srcset .}}>`,
+			data:    "a.png 1x, JavaScript:alert(1) 2x",
+			wantErr: true,
+		},
+		{
+			name:    "Srcset from SourceSet with javascript URL",
+			tmpl:    `<img {{srcset .}}>`,
+			data:    srcset.SourceSet{{URL: " javascript:alert(1)"}},
+			wantErr: true,
+		},
+		{
+			name:    "Srcset widths with javascript pattern",
+			tmpl:    `<img {{srcsetWidths "javascript:alert(%d)" 320}}>`,
+			wantErr: true,
+		},
+		{
+			name: "Srcset widths",
+			tmpl: `<img {{srcsetWidths "/img/hero-%d.jpg" 320 640}}>`,
+			want: `<img srcset="/img/hero-320.jpg 320w, /img/hero-640.jpg 640w">`,
+		},
+		{
+			name:    "Srcset without widths",
+			tmpl:    `<img {{srcsetWidths "/img/hero-%d.jpg"}}>`,
+			wantErr: true,
+		},
+		{
+			name:    "Srcset with zero width",
+			tmpl:    `<img {{srcsetWidths "/img/hero-%d.jpg" 0}}>`,
+			wantErr: true,
+		},
+		{
+			name: "Pick src",
+			tmpl: `<img {{pickSrc . 400}} {{srcset .}}>`,
+			data: "a.png 320w, b.png 640w",
+			want: `<img src="b.png" srcset="a.png 320w, b.png 640w">`,
+		},
+		{
+			name: "Pick src escaped",
+			tmpl: `<img {{pickSrc . 400}}>`,
+			data: `a.png?a=1&b="2" 1x`,
+			want: `<img src="a.png?a=1&amp;b=&#34;2&#34;">`,
+		},
+		{
+			name:    "Pick src with javascript URL",
+			tmpl:    `<img {{pickSrc . 400}}>`,
+			data:    "a.png 1x, JAVASCRIPT:alert(1) 2x",
+			wantErr: true,
+		},
+		{
+			name: "Pick src with allowed schemes",
+			tmpl: `<img {{pickSrc . 400}}>`,
+			data: "HTTPS://cdn.example.com/a.png 1x",
+			want: `<img src="HTTPS://cdn.example.com/a.png">`,
+		},
+		{
+			name:    "Pick src from empty set",
+			tmpl:    `<img {{pickSrc . 400}}>`,
+			data:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New(tt.name).Funcs(FuncMap()).Parse(tt.tmpl))
+
+			var sb strings.Builder
+			err := tmpl.Execute(&sb, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%q. Execute() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got := sb.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("%q. Execute() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
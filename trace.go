@@ -0,0 +1,98 @@
+package srcset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trace explains how a candidate was selected by TraceBestForViewport or
+// TraceBestForContext.
+type Trace struct {
+	SourceSize float64 // the evaluated source size in CSS pixels
+	DPR        float64
+	Steps      []TraceStep // one per candidate, in order
+	// Selected is the index of the selected candidate in Steps, or -1 if
+	// no candidate could be selected.
+	Selected int
+}
+
+// TraceStep explains the outcome for a single candidate.
+type TraceStep struct {
+	Source ImageSource
+	// Density is the effective density of the candidate at the source
+	// size. It is zero if the candidate was skipped.
+	Density float64
+	Skipped bool
+	Reason  string
+}
+
+// Source returns the selected candidate, or false if there is none.
+func (t Trace) Source() (ImageSource, bool) {
+	if t.Selected < 0 {
+		return ImageSource{}, false
+	}
+	return t.Steps[t.Selected].Source, true
+}
+
+// String renders the trace with a line per candidate, marking the selected
+// one with an asterisk.
+func (t Trace) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "source size %spx, device pixel ratio %s\n", formatFloat(t.SourceSize), formatFloat(t.DPR))
+	for i, step := range t.Steps {
+		mark := " "
+		if i == t.Selected {
+			mark = "*"
+		}
+		if step.Skipped {
+			fmt.Fprintf(&sb, "%s %s: %s\n", mark, step.Source, step.Reason)
+		} else {
+			fmt.Fprintf(&sb, "%s %s: %sx, %s\n", mark, step.Source, formatFloat(step.Density), step.Reason)
+		}
+	}
+	return sb.String()
+}
+
+// TraceBestForViewport selects a candidate like BestForViewport, and
+// returns a trace explaining the selection.
+func (s SourceSet) TraceBestForViewport(viewportWidth, dpr float64, sizes SizeList) Trace {
+	return s.TraceBestForContext(Viewport(viewportWidth), dpr, sizes)
+}
+
+// TraceBestForContext selects a candidate like BestForContext, and returns a
+// trace explaining the selection.
+func (s SourceSet) TraceBestForContext(ctx EvalContext, dpr float64, sizes SizeList) Trace {
+	var (
+		slot    = sizes.EvaluateContext(ctx)
+		density = func(c Candidate) (float64, bool) { return c.effectiveDensity(slot) }
+	)
+
+	selected, fallback := s.pickIndex(dpr, density)
+	var selectedD float64
+	if selected >= 0 {
+		selectedD, _ = density(s[selected].Candidate())
+	}
+
+	t := Trace{SourceSize: slot, DPR: dpr, Steps: make([]TraceStep, len(s)), Selected: selected}
+	for i, src := range s {
+		d, ok := density(src.Candidate())
+		step := TraceStep{Source: src, Density: d}
+		switch {
+		case !ok:
+			step.Skipped = true
+			step.Reason = "skipped: width descriptor without positive source size"
+		case i == selected && fallback:
+			step.Reason = "selected: largest density, as none reaches the device pixel ratio"
+		case i == selected:
+			step.Reason = "selected: smallest density reaching the device pixel ratio"
+		case d < dpr:
+			step.Reason = "rejected: density below the device pixel ratio"
+		case d == selectedD:
+			step.Reason = "rejected: same density as the selected candidate, which comes first"
+		default:
+			step.Reason = "rejected: density larger than that of the selected candidate"
+		}
+		t.Steps[i] = step
+	}
+	return t
+}
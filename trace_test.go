@@ -0,0 +1,82 @@
+package srcset
+
+import "testing"
+
+func Test_TraceBestForViewport(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		sizes       string
+		viewport    float64
+		dpr         float64
+		wantURL     string
+		wantReasons []string
+	}{
+		{
+			name:     "Smallest sufficient",
+			input:    "a.png 320w, b.png 640w, c.png 1280w, d.png 640w",
+			sizes:    "50vw",
+			viewport: 640,
+			dpr:      2,
+			wantURL:  "b.png",
+			wantReasons: []string{
+				"rejected: density below the device pixel ratio",
+				"selected: smallest density reaching the device pixel ratio",
+				"rejected: density larger than that of the selected candidate",
+				"rejected: same density as the selected candidate, which comes first",
+			},
+		},
+		{
+			name:     "Fallback",
+			input:    "a.png 1x, b.png 2x",
+			viewport: 1000,
+			dpr:      3,
+			wantURL:  "b.png",
+			wantReasons: []string{
+				"rejected: density below the device pixel ratio",
+				"selected: largest density, as none reaches the device pixel ratio",
+			},
+		},
+		{
+			name:     "No source size",
+			input:    "a.png 320w",
+			sizes:    "0px",
+			viewport: 1000,
+			dpr:      1,
+			wantReasons: []string{
+				"skipped: width descriptor without positive source size",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, sizes := Parse(tt.input), ParseSizes(tt.sizes)
+			trace := set.TraceBestForViewport(tt.viewport, tt.dpr, sizes)
+
+			got, _ := trace.Source()
+			want, _ := set.BestForViewport(tt.viewport, tt.dpr, sizes)
+			if got.URL != tt.wantURL || want.URL != tt.wantURL {
+				t.Errorf("%q. TraceBestForViewport() selected %q, BestForViewport() %q, want %q", tt.name, got.URL, want.URL, tt.wantURL)
+			}
+			if len(trace.Steps) != len(tt.wantReasons) {
+				t.Fatalf("%q. TraceBestForViewport() has %d steps, want %d", tt.name, len(trace.Steps), len(tt.wantReasons))
+			}
+			for i, step := range trace.Steps {
+				if step.Reason != tt.wantReasons[i] {
+					t.Errorf("%q. step %d reason = %q, want %q", tt.name, i, step.Reason, tt.wantReasons[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_Trace_String(t *testing.T) {
+	trace := Parse("a.png 320w, b.png 640w").TraceBestForViewport(320, 2, ParseSizes("100vw"))
+	want := "source size 320px, device pixel ratio 2\n" +
+		"  a.png 320w: 1x, rejected: density below the device pixel ratio\n" +
+		"* b.png 640w: 2x, selected: smallest density reaching the device pixel ratio\n"
+	if got := trace.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package srcset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation describes a candidate that does not conform to the authoring
+// requirements of the srcset attribute.
+type Violation struct {
+	Offset  int    // byte offset of the candidate in the input
+	URL     string // the URL of the candidate
+	Message string
+	Err     error // classifies the violation; one of the Err variables
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s at offset %d: %s", v.URL, v.Offset, v.Message)
+}
+
+// Validate checks s against the authoring conformance requirements of the
+// srcset attribute, and returns a violation for each problem found:
+//
+//   - URLs must be non-empty and must not start or end with a comma.
+//   - Candidates must not have both a width and a density descriptor, and
+//     height descriptors are only allowed together with a width descriptor.
+//   - Widths and densities must be greater than zero.
+//   - Width descriptors must not be mixed with density descriptors or
+//     candidates without descriptors.
+//   - No two candidates may have the same width or the same density, where
+//     a candidate without descriptors counts as 1x.
+//
+// Validate does not know about the sizes attribute, so it cannot check that
+// width descriptors are accompanied by one; use ValidateWithSizes for that.
+func (s SourceSet) Validate() []Violation {
+	var (
+		violations []Violation
+		widths     = map[int64]bool{}
+		densities  = map[float64]bool{}
+		hasWidths  = len(s.Widths()) > 0
+	)
+
+	for _, c := range s.Candidates() {
+		report := func(err error, format string, args ...interface{}) {
+			violations = append(violations, Violation{
+				Offset:  c.Offset,
+				URL:     c.URL,
+				Message: fmt.Sprintf(format, args...),
+				Err:     err,
+			})
+		}
+
+		switch {
+		case c.URL == "":
+			report(ErrEmptyURL, "empty URL")
+		case strings.HasPrefix(c.URL, ",") || strings.HasSuffix(c.URL, ","):
+			report(ErrInvalidURL, "URL starts or ends with a comma")
+		}
+
+		switch {
+		case c.HasWidth && c.HasDensity:
+			report(ErrMixedDescriptors, "both width and density descriptors")
+		case c.HasHeight && !c.HasWidth:
+			report(ErrMixedDescriptors, "height descriptor without width descriptor")
+		case hasWidths && !c.HasWidth:
+			report(ErrMixedDescriptors, "width descriptors mixed with other candidates")
+		}
+
+		if c.HasWidth {
+			if c.Width <= 0 {
+				report(ErrZeroWidth, "width %dw is not greater than zero", c.Width)
+			} else if widths[c.Width] {
+				report(ErrDuplicateDescriptor, "duplicate width %dw", c.Width)
+			}
+			widths[c.Width] = true
+		}
+		if c.HasHeight && c.Height <= 0 {
+			report(ErrInvalidHeight, "height %dh is not greater than zero", c.Height)
+		}
+		if !c.HasWidth {
+			d := c.density()
+			if d <= 0 {
+				report(ErrZeroDensity, "density %sx is not greater than zero", formatFloat(d))
+			} else if densities[d] {
+				report(ErrDuplicateDescriptor, "duplicate density %sx", formatFloat(d))
+			}
+			densities[d] = true
+		}
+	}
+
+	return violations
+}
+
+// ValidateWithSizes checks set together with the value of the sizes
+// attribute accompanying it, which is empty if the attribute is absent. In
+// addition to the violations reported by Validate, it reports:
+//
+//   - a sizes value that does not parse cleanly, with Err ErrInvalidSize
+//     and Offset into sizes;
+//   - width descriptors without sizes, at the first width candidate;
+//   - sizes without width descriptors, at offset 0.
+//
+// The last two are reported with Err ErrSizesMismatch.
+func ValidateWithSizes(set SourceSet, sizes string) []Violation {
+	var (
+		violations = set.Validate()
+		present    = strings.TrimSpace(sizes) != ""
+	)
+
+	if present {
+		ParseSizes(sizes, WithWarningHandler(func(w Warning) {
+			violations = append(violations, Violation{
+				Offset:  w.Offset,
+				Message: "invalid sizes: " + w.Message,
+				Err:     ErrInvalidSize,
+			})
+		}))
+	}
+
+	var first *Candidate
+	for _, c := range set.Candidates() {
+		if c.HasWidth {
+			first = &c
+			break
+		}
+	}
+	switch {
+	case first != nil && !present:
+		violations = append(violations, Violation{
+			Offset:  first.Offset,
+			URL:     first.URL,
+			Message: "width descriptors without sizes attribute",
+			Err:     ErrSizesMismatch,
+		})
+	case first == nil && present && len(set) > 0:
+		violations = append(violations, Violation{
+			Message: "sizes attribute without width descriptors",
+			Err:     ErrSizesMismatch,
+		})
+	}
+
+	return violations
+}
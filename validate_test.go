@@ -0,0 +1,131 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		set  SourceSet
+		want []Violation
+	}{
+		{
+			name: "Valid densities",
+			set:  Parse("a.png, b.png 2x"),
+		},
+		{
+			name: "Valid widths",
+			set:  Parse("a.png 320w, b.png 640w 480h"),
+		},
+		{
+			name: "Mixed descriptors",
+			set:  Parse("a.png 320w, b.png 2x"),
+			want: []Violation{{Offset: 12, URL: "b.png", Message: "width descriptors mixed with other candidates", Err: ErrMixedDescriptors}},
+		},
+		{
+			name: "Duplicate width",
+			set:  Parse("a.png 320w, b.png 320w"),
+			want: []Violation{{Offset: 12, URL: "b.png", Message: "duplicate width 320w", Err: ErrDuplicateDescriptor}},
+		},
+		{
+			name: "Duplicate default density",
+			set:  Parse("a.png, b.png 1x"),
+			want: []Violation{{Offset: 7, URL: "b.png", Message: "duplicate density 1x", Err: ErrDuplicateDescriptor}},
+		},
+		{
+			name: "Height without width",
+			set:  Parse("a.png 200h"),
+			want: []Violation{{Offset: 0, URL: "a.png", Message: "height descriptor without width descriptor", Err: ErrMixedDescriptors}},
+		},
+		{
+			name: "Zero density",
+			set:  Parse("a.png 0x"),
+			want: []Violation{{Offset: 0, URL: "a.png", Message: "density 0x is not greater than zero", Err: ErrZeroDensity}},
+		},
+		{
+			name: "Zero height",
+			set:  SourceSet{{URL: "a.png", Width: i(100), Height: i(0)}},
+			want: []Violation{{Offset: 0, URL: "a.png", Message: "height 0h is not greater than zero", Err: ErrInvalidHeight}},
+		},
+		{
+			name: "Constructed set",
+			set: SourceSet{
+				{URL: "", Density: fl(1)},
+				{URL: ",b.png", Width: i(0), Density: fl(2)},
+			},
+			want: []Violation{
+				{Offset: 0, URL: "", Message: "empty URL", Err: ErrEmptyURL},
+				{Offset: 0, URL: "", Message: "width descriptors mixed with other candidates", Err: ErrMixedDescriptors},
+				{Offset: 0, URL: ",b.png", Message: "URL starts or ends with a comma", Err: ErrInvalidURL},
+				{Offset: 0, URL: ",b.png", Message: "both width and density descriptors", Err: ErrMixedDescriptors},
+				{Offset: 0, URL: ",b.png", Message: "width 0w is not greater than zero", Err: ErrZeroWidth},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.Validate(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Validate() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ValidateWithSizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		set   SourceSet
+		sizes string
+		want  []Violation
+	}{
+		{
+			name:  "Widths with sizes",
+			set:   Parse("a.png 320w, b.png 640w"),
+			sizes: "(max-width: 600px) 100vw, 50vw",
+		},
+		{
+			name: "Densities without sizes",
+			set:  Parse("a.png, b.png 2x"),
+		},
+		{
+			name: "Widths without sizes",
+			set:  Parse("a.png 2x, b.png 640w"),
+			want: []Violation{
+				{Offset: 0, URL: "a.png", Message: "width descriptors mixed with other candidates", Err: ErrMixedDescriptors},
+				{Offset: 10, URL: "b.png", Message: "width descriptors without sizes attribute", Err: ErrSizesMismatch},
+			},
+		},
+		{
+			name:  "Blank sizes",
+			set:   Parse("a.png 320w"),
+			sizes: " ",
+			want:  []Violation{{Offset: 0, URL: "a.png", Message: "width descriptors without sizes attribute", Err: ErrSizesMismatch}},
+		},
+		{
+			name:  "Sizes without widths",
+			set:   Parse("a.png 1x, b.png 2x"),
+			sizes: "100vw",
+			want:  []Violation{{Message: "sizes attribute without width descriptors", Err: ErrSizesMismatch}},
+		},
+		{
+			name:  "Empty set",
+			sizes: "100vw",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateWithSizes(tt.set, tt.sizes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. ValidateWithSizes() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	got := ValidateWithSizes(Parse("a.png 320w"), "50%, 100vw")
+	if len(got) != 1 || got[0].Err != ErrInvalidSize || got[0].Offset != 0 {
+		t.Errorf("ValidateWithSizes() = %v, want one invalid size at offset 0", got)
+	}
+}
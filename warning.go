@@ -0,0 +1,75 @@
+package srcset
+
+import "fmt"
+
+// WarningKind classifies the problems reported through a warning handler.
+type WarningKind int
+
+const (
+	// DroppedCandidate is reported when a candidate is discarded because of
+	// invalid descriptors.
+	DroppedCandidate WarningKind = iota
+	// SkippedGarbage is reported when extraneous commas between candidates
+	// are skipped.
+	SkippedGarbage
+	// Suspicious is reported for constructs that the parser recovers from,
+	// but that are parse errors according to the spec, such as an URL with
+	// several trailing commas or an unterminated parenthesis.
+	Suspicious
+	// Fixable is reported for invalid constructs that a lenient option
+	// accepted, such as an uppercase descriptor suffix. The Fix of the
+	// warning holds the valid replacement for its Text.
+	Fixable
+)
+
+func (k WarningKind) String() string {
+	switch k {
+	case DroppedCandidate:
+		return "dropped candidate"
+	case SkippedGarbage:
+		return "skipped garbage"
+	case Suspicious:
+		return "suspicious"
+	case Fixable:
+		return "fixable"
+	default:
+		return fmt.Sprintf("WarningKind(%d)", int(k))
+	}
+}
+
+// Warning describes a problem that the parser recovered from.
+type Warning struct {
+	Kind    WarningKind
+	Offset  int    // byte offset of Text in the input
+	Line    int    // line of Text in the input, starting at 1
+	Column  int    // column of Text in the input, starting at 1
+	Text    string // the offending part of the input
+	Message string
+	Err     error  // classifies the problem; one of the Err variables
+	Fix     string // the replacement for Text, for Fixable warnings
+}
+
+func (w Warning) String() string {
+	if w.Line > 0 {
+		return fmt.Sprintf("%s at %d:%d: %s (%q)", w.Kind, w.Line, w.Column, w.Message, w.Text)
+	}
+	return fmt.Sprintf("%s at offset %d: %s (%q)", w.Kind, w.Offset, w.Message, w.Text)
+}
+
+// ParseError is returned by ParseStrict and lists every problem found in the
+// input.
+type ParseError struct {
+	Input    string
+	Warnings []Warning
+}
+
+func (e *ParseError) Error() string {
+	switch len(e.Warnings) {
+	case 0:
+		return "srcset: invalid input"
+	case 1:
+		return "srcset: " + e.Warnings[0].String()
+	default:
+		return fmt.Sprintf("srcset: %s (and %d more)", e.Warnings[0], len(e.Warnings)-1)
+	}
+}
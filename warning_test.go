@@ -0,0 +1,76 @@
+package srcset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_warnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Warning
+	}{
+		{
+			name:  "No warnings",
+			input: "a.png 1x, b.png 2x",
+			want:  nil,
+		},
+		{
+			name:  "Dropped candidate",
+			input: "a.png 1x 2x, b.png 2x",
+			want: []Warning{
+				{Kind: DroppedCandidate, Offset: 0, Line: 1, Column: 1, Text: "a.png 1x 2x", Message: "invalid descriptors", Err: ErrDuplicateDescriptor},
+			},
+		},
+		{
+			name:  "Extraneous commas",
+			input: "a.png 1x,, b.png 2x",
+			want: []Warning{
+				{Kind: SkippedGarbage, Offset: 9, Line: 1, Column: 10, Text: ", ", Message: "extraneous commas", Err: ErrSyntax},
+			},
+		},
+		{
+			name:  "Multiple trailing commas",
+			input: "a.png,, b.png 2x",
+			want: []Warning{
+				{Kind: Suspicious, Offset: 0, Line: 1, Column: 1, Text: "a.png,,", Message: "multiple trailing commas after URL", Err: ErrSyntax},
+			},
+		},
+		{
+			name:  "Multi-line input",
+			input: "a.png 1x,\n  b.png 1q,\n  c.png 2x",
+			want: []Warning{
+				{Kind: DroppedCandidate, Offset: 12, Line: 2, Column: 3, Text: "b.png 1q", Message: "invalid descriptors", Err: ErrInvalidDescriptor},
+			},
+		},
+		{
+			name:  "Unterminated parenthesis",
+			input: "a.png (1x",
+			want: []Warning{
+				{Kind: Suspicious, Offset: 0, Line: 1, Column: 1, Text: "a.png (1x", Message: "unterminated parenthesis", Err: ErrSyntax},
+				{Kind: DroppedCandidate, Offset: 0, Line: 1, Column: 1, Text: "a.png (1x", Message: "invalid descriptors", Err: ErrInvalidDescriptor},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Warning
+			Parse(tt.input, WithWarningHandler(func(w Warning) {
+				got = append(got, w)
+			}))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q. Parse() warnings = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseError_position(t *testing.T) {
+	_, err := ParseStrict("a.png 1x,\n  b.png 1q")
+	want := `srcset: dropped candidate at 2:3: invalid descriptors ("b.png 1q")`
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseStrict() error = %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,71 @@
+// Package wpt runs the srcset parser against test cases in the format of the
+// Web Platform Tests for the srcset attribute, as found in
+// html/semantics/embedded-content/the-img-element/srcset/parse-a-srcset-attribute.html.
+//
+// Each test case is an img element with a srcset attribute and a data-expect
+// attribute holding the URL a browser with a device pixel ratio of 1 is
+// expected to pick, or an empty string if no candidate is valid.
+package wpt
+
+import (
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+
+	"github.com/lukasbob/srcset"
+)
+
+// viewportWidth is the viewport width used to evaluate width descriptors.
+const viewportWidth = 800
+
+var regexImg = regexp.MustCompile(`<img\s+srcset="([^"]*)"\s+data-expect="([^"]*)"`)
+
+// Case is a single test case.
+type Case struct {
+	Srcset string
+	Expect string
+}
+
+// Result is the outcome of running a Case.
+type Result struct {
+	Case
+	Got  string
+	Pass bool
+}
+
+// Load reads the test cases from a WPT test file. Character references in
+// the attribute values are decoded.
+func Load(r io.Reader) ([]Case, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []Case
+	for _, m := range regexImg.FindAllSubmatch(b, -1) {
+		cases = append(cases, Case{
+			Srcset: html.UnescapeString(string(m[1])),
+			Expect: html.UnescapeString(string(m[2])),
+		})
+	}
+
+	return cases, nil
+}
+
+// Run parses the srcset of every case with opts, picks a candidate for a
+// device pixel ratio of 1, and compares it to the expected URL.
+func Run(cases []Case, opts ...srcset.Option) []Result {
+	parser := srcset.NewParser(opts...)
+	results := make([]Result, len(cases))
+
+	for i, c := range cases {
+		var got string
+		if src, ok := parser.Parse(c.Srcset).BestForViewport(viewportWidth, 1, nil); ok {
+			got = src.URL
+		}
+		results[i] = Result{Case: c, Got: got, Pass: got == c.Expect}
+	}
+
+	return results
+}
@@ -0,0 +1,37 @@
+package wpt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lukasbob/srcset"
+)
+
+// knownFailures lists the cases where the parser is known to differ from
+// browsers.
+var knownFailures = map[string]bool{}
+
+func Test_Run(t *testing.T) {
+	f, err := os.Open("testdata/parse-a-srcset-attribute.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cases, err := Load(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("Load() found no test cases")
+	}
+
+	for _, r := range Run(cases, srcset.WithSpecProfile(srcset.Living)) {
+		if !r.Pass && !knownFailures[r.Srcset] {
+			t.Errorf("srcset=%q: got %q, want %q", r.Srcset, r.Got, r.Expect)
+		}
+		if r.Pass && knownFailures[r.Srcset] {
+			t.Errorf("srcset=%q: passes, but is listed as a known failure", r.Srcset)
+		}
+	}
+}